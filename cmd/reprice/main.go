@@ -0,0 +1,136 @@
+// Command reprice applies CalculateDiscount to every row of a CSV price list.
+//
+// Usage:
+//
+//	reprice [flags] < prices.csv > repriced.csv
+//
+// Rows that cannot be priced are skipped and listed in the error report, and
+// the command then exits with status 2 unless -allow-errors is set.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"example.com/testing/math"
+)
+
+// errRowsFailed reports that some rows were skipped
+var errRowsFailed = errors.New("some rows could not be repriced")
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "reprice:", err)
+		if errors.Is(err, errRowsFailed) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// ruleFlag collects repeated -rule column=value:discount flags
+type ruleFlag []math.RepriceRule
+
+func (f *ruleFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *ruleFlag) Set(s string) error {
+	match, discount, ok := strings.Cut(s, ":")
+	column, value, ok2 := strings.Cut(match, "=")
+	if !ok || !ok2 {
+		return fmt.Errorf("want column=value:discount, got %q", s)
+	}
+	d, err := strconv.ParseFloat(discount, 64)
+	if err != nil {
+		return fmt.Errorf("parse discount: %w", err)
+	}
+	*f = append(*f, math.RepriceRule{Column: column, Value: value, Discount: d})
+	return nil
+}
+
+func run() error {
+	var rules ruleFlag
+	flag.Var(&rules, "rule", "column=value:discount rule, checked in order before other discounts (repeatable)")
+	var (
+		in             = flag.String("in", "", "input CSV file (default stdin)")
+		out            = flag.String("out", "", "output CSV file (default stdout)")
+		errorsPath     = flag.String("errors", "", "write the per-row error report to this file (default stderr)")
+		skuColumn      = flag.String("sku-column", "sku", "column holding the SKU")
+		priceColumn    = flag.String("price-column", "price", "column holding the price")
+		discountColumn = flag.String("discount-column", "", "column holding a per-row discount percentage")
+		discount       = flag.Float64("discount", 0, "discount percentage for rows without their own")
+		memberColumn   = flag.String("member-column", "", "column holding a per-row member flag")
+		member         = flag.Bool("member", false, "apply the member discount to every row")
+		allowErrors    = flag.Bool("allow-errors", false, "exit with status 0 even when some rows are skipped")
+	)
+	flag.Parse()
+
+	var r io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var w io.Writer = os.Stdout
+	var outFile *os.File
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		outFile, w = f, f
+	}
+
+	report, err := math.RepriceCSV(r, w, math.RepriceOptions{
+		SKUColumn:      *skuColumn,
+		PriceColumn:    *priceColumn,
+		DiscountColumn: *discountColumn,
+		Discount:       *discount,
+		MemberColumn:   *memberColumn,
+		IsMember:       *member,
+		Rules:          rules,
+	})
+	// Close explicitly: a failed close can mean the repriced file is truncated
+	if outFile != nil {
+		if cerr := outFile.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "reprice: %d rows, %d written, %d errors\n", report.Rows, report.Written, len(report.Errors))
+	if len(report.Errors) == 0 {
+		return nil
+	}
+	if *errorsPath == "" {
+		err = math.WriteErrorReport(os.Stderr, report.Errors)
+	} else {
+		f, ferr := os.Create(*errorsPath)
+		if ferr != nil {
+			return ferr
+		}
+		err = math.WriteErrorReport(f, report.Errors)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if *allowErrors {
+		return nil
+	}
+	return errRowsFailed
+}
@@ -0,0 +1,170 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// LineItem is a quantity of one product at a unit price
+type LineItem struct {
+	SKU       string
+	UnitPrice Money
+	Quantity  int
+}
+
+// OrderDiscount is a discount on the whole order, either a percentage of the
+// running total or a fixed amount. Set exactly one of Percent and Amount.
+type OrderDiscount struct {
+	Name    string
+	Percent float64
+	Amount  Money
+}
+
+// Cart is a set of line items in a single currency with order-level discounts
+type Cart struct {
+	Currency  Currency
+	Lines     []LineItem
+	Discounts []OrderDiscount
+}
+
+// LineBreakdown is one line of a cart with its share of the order discounts
+type LineBreakdown struct {
+	SKU      string
+	Quantity int
+	Subtotal Money
+	Discount Money
+	Total    Money
+}
+
+// AppliedDiscount is the amount an order discount took off the running total
+type AppliedDiscount struct {
+	Name   string
+	Amount Money
+}
+
+// CartBreakdown is the full result of pricing a cart. The line amounts always
+// sum exactly to the order amounts.
+type CartBreakdown struct {
+	Lines     []LineBreakdown
+	Discounts []AppliedDiscount
+	Subtotal  Money
+	Discount  Money
+	Total     Money
+}
+
+// NewCart returns an empty cart priced in c
+func NewCart(c Currency) *Cart {
+	return &Cart{Currency: c}
+}
+
+// Add adds a line to the cart
+func (c *Cart) Add(sku string, unitPrice Money, quantity int) error {
+	if quantity <= 0 {
+		return &InvalidInputError{Value: quantity}
+	}
+	if unitPrice.Amount < 0 {
+		return fmt.Errorf("invalid unit price for %s: %v", sku, unitPrice)
+	}
+	if unitPrice.Currency != c.Currency {
+		return &CurrencyMismatchError{Want: c.Currency.Code, Got: unitPrice.Currency.Code}
+	}
+	c.Lines = append(c.Lines, LineItem{SKU: sku, UnitPrice: unitPrice, Quantity: quantity})
+	return nil
+}
+
+// ApplyDiscount adds an order-level discount. Discounts are applied in the
+// order they were added, each to the total left by the ones before it.
+func (c *Cart) ApplyDiscount(d OrderDiscount) error {
+	if d.Percent != 0 && d.Amount.Amount != 0 {
+		return fmt.Errorf("discount %q: set either a percentage or an amount", d.Name)
+	}
+	if d.Percent < 0 || d.Percent > 100 || d.Amount.Amount < 0 {
+		return fmt.Errorf("discount %q: invalid input", d.Name)
+	}
+	if d.Amount.Amount != 0 && d.Amount.Currency != c.Currency {
+		return &CurrencyMismatchError{Want: c.Currency.Code, Got: d.Amount.Currency.Code}
+	}
+	c.Discounts = append(c.Discounts, d)
+	return nil
+}
+
+// Totals prices the cart and allocates the order discounts back to the lines
+// pro rata to their subtotals
+func (c *Cart) Totals() (CartBreakdown, error) {
+	b := CartBreakdown{
+		Lines:    make([]LineBreakdown, len(c.Lines)),
+		Subtotal: Money{Currency: c.Currency},
+		Discount: Money{Currency: c.Currency},
+	}
+	subtotals := make([]int64, len(c.Lines))
+	for i, l := range c.Lines {
+		if l.Quantity != 0 && l.UnitPrice.Amount > math.MaxInt64/int64(l.Quantity) {
+			return CartBreakdown{}, fmt.Errorf("overflow at line %d: %w", i, &InvalidInputError{Value: l.Quantity})
+		}
+		subtotals[i] = l.UnitPrice.Amount * int64(l.Quantity)
+		if subtotals[i] > math.MaxInt64-b.Subtotal.Amount {
+			return CartBreakdown{}, fmt.Errorf("overflow in subtotal at line %d: %w", i, &InvalidInputError{Value: l.Quantity})
+		}
+		b.Subtotal.Amount += subtotals[i]
+	}
+
+	remaining := b.Subtotal.Amount
+	for _, d := range c.Discounts {
+		off := d.Amount.Amount
+		if d.Percent != 0 {
+			off = int64(math.Round(float64(remaining) * d.Percent / 100))
+		}
+		off = min(off, remaining)
+		remaining -= off
+		b.Discounts = append(b.Discounts, AppliedDiscount{Name: d.Name, Amount: Money{Amount: off, Currency: c.Currency}})
+	}
+	b.Discount.Amount = b.Subtotal.Amount - remaining
+	b.Total = Money{Amount: remaining, Currency: c.Currency}
+
+	shares := allocate(b.Discount.Amount, subtotals)
+	for i, l := range c.Lines {
+		b.Lines[i] = LineBreakdown{
+			SKU:      l.SKU,
+			Quantity: l.Quantity,
+			Subtotal: Money{Amount: subtotals[i], Currency: c.Currency},
+			Discount: Money{Amount: shares[i], Currency: c.Currency},
+			Total:    Money{Amount: subtotals[i] - shares[i], Currency: c.Currency},
+		}
+	}
+	return b, nil
+}
+
+// allocate splits amount across weights pro rata using the largest remainder
+// method, so the shares sum exactly to amount. Ties go to the earlier weight.
+func allocate(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 || amount == 0 {
+		return shares
+	}
+
+	remainders := make([]int64, len(weights))
+	allocated := int64(0)
+	bigAmount, bigTotal := big.NewInt(amount), big.NewInt(total)
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(bigAmount, big.NewInt(w)), bigTotal, new(big.Int))
+		shares[i] = q.Int64()
+		remainders[i] = r.Int64()
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order[:amount-allocated] {
+		shares[i]++
+	}
+	return shares
+}
@@ -0,0 +1,156 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func usd(cents int64) Money {
+	return Money{Amount: cents, Currency: USD}
+}
+
+func TestCart_Totals(t *testing.T) {
+	tests := []struct {
+		name          string
+		lines         []LineItem
+		discounts     []OrderDiscount
+		wantSubtotal  int64
+		wantDiscount  int64
+		wantTotal     int64
+		wantLineTotal []int64
+	}{
+		{
+			name:          "NoDiscount",
+			lines:         []LineItem{{"A", usd(250), 2}, {"B", usd(100), 1}},
+			wantSubtotal:  600,
+			wantDiscount:  0,
+			wantTotal:     600,
+			wantLineTotal: []int64{500, 100},
+		},
+		{
+			name:          "PercentSplitsProRata",
+			lines:         []LineItem{{"A", usd(1000), 3}, {"B", usd(1000), 1}},
+			discounts:     []OrderDiscount{{Name: "10OFF", Percent: 10}},
+			wantSubtotal:  4000,
+			wantDiscount:  400,
+			wantTotal:     3600,
+			wantLineTotal: []int64{2700, 900},
+		},
+		{
+			name:          "RemainderCentsDistributed",
+			lines:         []LineItem{{"A", usd(100), 1}, {"B", usd(100), 1}, {"C", usd(100), 1}},
+			discounts:     []OrderDiscount{{Name: "ONE", Amount: usd(100)}},
+			wantSubtotal:  300,
+			wantDiscount:  100,
+			wantTotal:     200,
+			wantLineTotal: []int64{66, 67, 67},
+		},
+		{
+			name:          "StackedDiscounts",
+			lines:         []LineItem{{"A", usd(2000), 1}},
+			discounts:     []OrderDiscount{{Name: "5USD", Amount: usd(500)}, {Name: "HALF", Percent: 50}},
+			wantSubtotal:  2000,
+			wantDiscount:  1250,
+			wantTotal:     750,
+			wantLineTotal: []int64{750},
+		},
+		{
+			name:          "DiscountCappedAtSubtotal",
+			lines:         []LineItem{{"A", usd(300), 1}},
+			discounts:     []OrderDiscount{{Name: "BIG", Amount: usd(1000)}},
+			wantSubtotal:  300,
+			wantDiscount:  300,
+			wantTotal:     0,
+			wantLineTotal: []int64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := NewCart(USD)
+			for _, l := range tt.lines {
+				if err := cart.Add(l.SKU, l.UnitPrice, l.Quantity); err != nil {
+					t.Fatalf("Add(%s) failed: %v", l.SKU, err)
+				}
+			}
+			for _, d := range tt.discounts {
+				if err := cart.ApplyDiscount(d); err != nil {
+					t.Fatalf("ApplyDiscount(%s) failed: %v", d.Name, err)
+				}
+			}
+
+			got, err := cart.Totals()
+			if err != nil {
+				t.Fatalf("Totals() failed: %v", err)
+			}
+			if got.Subtotal.Amount != tt.wantSubtotal || got.Discount.Amount != tt.wantDiscount || got.Total.Amount != tt.wantTotal {
+				t.Errorf("Totals() = %v - %v = %v, want %d - %d = %d",
+					got.Subtotal, got.Discount, got.Total, tt.wantSubtotal, tt.wantDiscount, tt.wantTotal)
+			}
+
+			var lineTotal, lineDiscount int64
+			for i, l := range got.Lines {
+				if l.Total.Amount != tt.wantLineTotal[i] {
+					t.Errorf("line %s total = %v, want %d", l.SKU, l.Total, tt.wantLineTotal[i])
+				}
+				lineTotal += l.Total.Amount
+				lineDiscount += l.Discount.Amount
+			}
+			if lineTotal != got.Total.Amount || lineDiscount != got.Discount.Amount {
+				t.Errorf("lines sum to %d total and %d discount, order has %v and %v", lineTotal, lineDiscount, got.Total, got.Discount)
+			}
+		})
+	}
+}
+
+func TestCart_Errors(t *testing.T) {
+	cart := NewCart(USD)
+
+	var inputErr *InvalidInputError
+	if err := cart.Add("A", usd(100), 0); !errors.As(err, &inputErr) {
+		t.Errorf("Add with zero quantity error = %v, want *InvalidInputError", err)
+	}
+
+	var mismatch *CurrencyMismatchError
+	if err := cart.Add("A", Money{Amount: 100, Currency: EUR}, 1); !errors.As(err, &mismatch) {
+		t.Errorf("Add in EUR error = %v, want *CurrencyMismatchError", err)
+	}
+	if err := cart.ApplyDiscount(OrderDiscount{Name: "EUR", Amount: Money{Amount: 1, Currency: EUR}}); !errors.As(err, &mismatch) {
+		t.Errorf("ApplyDiscount in EUR error = %v, want *CurrencyMismatchError", err)
+	}
+
+	if err := cart.ApplyDiscount(OrderDiscount{Name: "BOTH", Percent: 5, Amount: usd(1)}); err == nil {
+		t.Error("ApplyDiscount with percent and amount should return an error")
+	}
+	if err := cart.ApplyDiscount(OrderDiscount{Name: "TOOMUCH", Percent: 150}); err == nil {
+		t.Error("ApplyDiscount with 150% should return an error")
+	}
+
+	huge := NewCart(USD)
+	_ = huge.Add("A", usd(math.MaxInt64/2), 3)
+	if _, err := huge.Totals(); !errors.As(err, &inputErr) {
+		t.Errorf("Totals with an overflowing line error = %v, want *InvalidInputError", err)
+	}
+
+	huge = NewCart(USD)
+	_ = huge.Add("A", usd(math.MaxInt64/2+1), 1)
+	_ = huge.Add("B", usd(math.MaxInt64/2+1), 1)
+	if _, err := huge.Totals(); !errors.As(err, &inputErr) {
+		t.Errorf("Totals with an overflowing subtotal error = %v, want *InvalidInputError", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	weights := []int64{1, 1, 1, 1, 1, 1, 1}
+	for amount := int64(0); amount <= 50; amount++ {
+		shares := allocate(amount, weights)
+		var sum int64
+		for _, s := range shares {
+			sum += s
+		}
+		if sum != amount {
+			t.Fatalf("allocate(%d, %v) = %v, sums to %d", amount, weights, shares, sum)
+		}
+	}
+}
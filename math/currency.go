@@ -0,0 +1,141 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency and the number of digits in its minor unit
+type Currency struct {
+	Code     string
+	Exponent int
+}
+
+var (
+	USD = Currency{Code: "USD", Exponent: 2}
+	EUR = Currency{Code: "EUR", Exponent: 2}
+	GBP = Currency{Code: "GBP", Exponent: 2}
+	CHF = Currency{Code: "CHF", Exponent: 2}
+	CAD = Currency{Code: "CAD", Exponent: 2}
+	AUD = Currency{Code: "AUD", Exponent: 2}
+	NGN = Currency{Code: "NGN", Exponent: 2}
+	JPY = Currency{Code: "JPY", Exponent: 0}
+	KRW = Currency{Code: "KRW", Exponent: 0}
+	BHD = Currency{Code: "BHD", Exponent: 3}
+	KWD = Currency{Code: "KWD", Exponent: 3}
+	JOD = Currency{Code: "JOD", Exponent: 3}
+	OMR = Currency{Code: "OMR", Exponent: 3}
+	TND = Currency{Code: "TND", Exponent: 3}
+)
+
+var currencies = map[string]Currency{}
+
+func init() {
+	for _, c := range []Currency{USD, EUR, GBP, CHF, CAD, AUD, NGN, JPY, KRW, BHD, KWD, JOD, OMR, TND} {
+		currencies[c.Code] = c
+	}
+}
+
+// UnknownCurrencyError is returned for a currency code that is not known
+type UnknownCurrencyError struct {
+	Code string
+}
+
+func (e *UnknownCurrencyError) Error() string {
+	return fmt.Sprintf("unknown currency: %q", e.Code)
+}
+
+// CurrencyMismatchError is returned when amounts in different currencies are combined
+type CurrencyMismatchError struct {
+	Want, Got string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("currency mismatch: %s and %s", e.Want, e.Got)
+}
+
+// LookupCurrency returns the currency for an ISO 4217 code
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, &UnknownCurrencyError{Code: code}
+	}
+	return c, nil
+}
+
+// Money is an amount held in the minor units of its currency,
+// e.g. cents for USD or fils for BHD
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// NewMoney converts a decimal amount to Money, rounding to the currency's minor unit
+func NewMoney(amount float64, code string) (Money, error) {
+	c, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: int64(math.Round(amount * math.Pow10(c.Exponent))), Currency: c}, nil
+}
+
+// Float returns the amount in major units
+func (m Money) Float() float64 {
+	return float64(m.Amount) / math.Pow10(m.Currency.Exponent)
+}
+
+// String formats the amount with the currency's number of decimals, e.g. "12.50 USD"
+func (m Money) String() string {
+	sign := ""
+	// A uint64 magnitude keeps math.MinInt64, which has no positive int64, intact
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatUint(amount, 10)
+	exp := m.Currency.Exponent
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	return sign + digits + " " + m.Currency.Code
+}
+
+// Add returns m + o; both must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, &CurrencyMismatchError{Want: m.Currency.Code, Got: o.Currency.Code}
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("addition overflow")
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o; both must be in the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, &CurrencyMismatchError{Want: m.Currency.Code, Got: o.Currency.Code}
+	}
+	diff := m.Amount - o.Amount
+	if (o.Amount > 0 && diff > m.Amount) || (o.Amount < 0 && diff < m.Amount) {
+		return Money{}, fmt.Errorf("subtraction overflow")
+	}
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// CalculateDiscountMoney is CalculateDiscount for Money, rounding the result
+// to the minor unit of the price's currency instead of always to two decimals
+func CalculateDiscountMoney(price Money, discount float64, isMember bool) (Money, error) {
+	if price.Amount < 0 || discount < 0 || discount > 100 {
+		return Money{}, fmt.Errorf("invalid input")
+	}
+	final := applyDiscount(float64(price.Amount), discount, isMember)
+	return Money{Amount: int64(math.Round(final)), Currency: price.Currency}, nil
+}
@@ -0,0 +1,130 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     float64
+		code       string
+		wantAmount int64
+		wantString string
+		wantErr    bool
+	}{
+		{"USD", 12.5, "USD", 1250, "12.50 USD", false},
+		{"LowercaseCode", 0.07, "eur", 7, "0.07 EUR", false},
+		{"JPYHasNoDecimals", 1234.6, "JPY", 1235, "1235 JPY", false},
+		{"BHDHasThreeDecimals", 1.2345, "BHD", 1235, "1.235 BHD", false},
+		{"Negative", -0.5, "KWD", -500, "-0.500 KWD", false},
+		{"UnknownCurrency", 1, "XYZ", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewMoney(%v, %q) error = %v, wantErr %v", tt.amount, tt.code, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var curErr *UnknownCurrencyError
+				if !errors.As(err, &curErr) {
+					t.Errorf("NewMoney(%v, %q) error type = %T, want *UnknownCurrencyError", tt.amount, tt.code, err)
+				}
+				return
+			}
+			if got.Amount != tt.wantAmount {
+				t.Errorf("NewMoney(%v, %q).Amount = %d, want %d", tt.amount, tt.code, got.Amount, tt.wantAmount)
+			}
+			if got.String() != tt.wantString {
+				t.Errorf("NewMoney(%v, %q).String() = %q, want %q", tt.amount, tt.code, got.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestCalculateDiscountMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		code     string
+		discount float64
+		isMember bool
+		want     string
+		wantErr  bool
+	}{
+		{"USDMatchesCalculateDiscount", 100, "USD", 10, true, "85.50 USD", false},
+		{"JPYRoundsToYen", 999, "JPY", 15, true, "807 JPY", false},
+		{"BHDRoundsToFils", 10.005, "BHD", 10, false, "9.005 BHD", false},
+		{"InvalidDiscount", 100, "USD", 101, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := NewMoney(tt.amount, tt.code)
+			if err != nil {
+				t.Fatalf("NewMoney(%v, %q) failed: %v", tt.amount, tt.code, err)
+			}
+			got, err := CalculateDiscountMoney(price, tt.discount, tt.isMember)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateDiscountMoney(%v, %v, %v) error = %v, wantErr %v", price, tt.discount, tt.isMember, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("CalculateDiscountMoney(%v, %v, %v) = %v, want %s", price, tt.discount, tt.isMember, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_AddSub(t *testing.T) {
+	a := Money{Amount: 150, Currency: USD}
+	b := Money{Amount: 25, Currency: USD}
+
+	sum, err := a.Add(b)
+	if err != nil || sum.Amount != 175 {
+		t.Errorf("%v.Add(%v) = %v, %v, want 1.75 USD", a, b, sum, err)
+	}
+	diff, err := a.Sub(b)
+	if err != nil || diff.Amount != 125 {
+		t.Errorf("%v.Sub(%v) = %v, %v, want 1.25 USD", a, b, diff, err)
+	}
+
+	_, err = a.Add(Money{Amount: 1, Currency: JPY})
+	var mismatch *CurrencyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Add across currencies error = %v, want *CurrencyMismatchError", err)
+	}
+	if mismatch.Want != "USD" || mismatch.Got != "JPY" {
+		t.Errorf("CurrencyMismatchError = %+v, want USD and JPY", mismatch)
+	}
+}
+
+func TestMoney_String_Extremes(t *testing.T) {
+	if got, want := usd(math.MinInt64).String(), "-92233720368547758.08 USD"; got != want {
+		t.Errorf("MinInt64 String() = %q, want %q", got, want)
+	}
+	if got, want := usd(math.MaxInt64).String(), "92233720368547758.07 USD"; got != want {
+		t.Errorf("MaxInt64 String() = %q, want %q", got, want)
+	}
+}
+
+func TestMoney_AddSub_Overflow(t *testing.T) {
+	if got, err := usd(math.MaxInt64).Add(usd(1)); err == nil {
+		t.Errorf("MaxInt64 + 1 = %v, want an overflow error", got)
+	}
+	if got, err := usd(math.MinInt64).Add(usd(-1)); err == nil {
+		t.Errorf("MinInt64 + -1 = %v, want an overflow error", got)
+	}
+	if got, err := usd(math.MinInt64).Sub(usd(1)); err == nil {
+		t.Errorf("MinInt64 - 1 = %v, want an overflow error", got)
+	}
+	if got, err := usd(0).Sub(usd(math.MinInt64)); err == nil {
+		t.Errorf("0 - MinInt64 = %v, want an overflow error", got)
+	}
+	if got, err := usd(-1).Sub(usd(math.MaxInt64)); err != nil || got.Amount != math.MinInt64 {
+		t.Errorf("-1 - MaxInt64 = %v, %v, want MinInt64", got, err)
+	}
+}
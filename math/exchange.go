@@ -0,0 +1,230 @@
+package math
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const rateDateLayout = "2006-01-02"
+
+// ExchangeRate says that one unit of From is worth Rate units of To on Date
+type ExchangeRate struct {
+	From string
+	To   string
+	Rate float64
+	Date time.Time
+}
+
+// Conversion records an amount converted between currencies and the rate used
+type Conversion struct {
+	From Money
+	To   Money
+	Rate float64
+	// Date is the date of the rate used; for a triangulated conversion it is
+	// the older of the two legs
+	Date time.Time
+	// Via is the base currency used for triangulation, empty for a direct rate
+	Via string
+}
+
+// RateNotFoundError is returned when no rate converts From to To on a date
+type RateNotFoundError struct {
+	From, To string
+	On       time.Time
+}
+
+func (e *RateNotFoundError) Error() string {
+	return fmt.Sprintf("no exchange rate from %s to %s on %s", e.From, e.To, e.On.Format(rateDateLayout))
+}
+
+// RateTable holds dated exchange rates. Pairs without a rate of their own are
+// converted through Base when it is set.
+type RateTable struct {
+	Base  string
+	rates map[[2]string][]ExchangeRate
+}
+
+// NewRateTable builds a table from rates; base may be empty to disable triangulation
+func NewRateTable(base string, rates []ExchangeRate) (*RateTable, error) {
+	t := &RateTable{Base: strings.ToUpper(base), rates: make(map[[2]string][]ExchangeRate)}
+	for _, r := range rates {
+		if !(r.Rate > 0) || math.IsInf(r.Rate, 0) {
+			return nil, fmt.Errorf("invalid rate %s/%s: %v", r.From, r.To, r.Rate)
+		}
+		r.From, r.To = strings.ToUpper(r.From), strings.ToUpper(r.To)
+		key := [2]string{r.From, r.To}
+		t.rates[key] = append(t.rates[key], r)
+	}
+	for _, rs := range t.rates {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].Date.Before(rs[j].Date) })
+	}
+	return t, nil
+}
+
+// LoadRates reads dated rates from a .json or .csv file. CSV files have the
+// columns date,from,to,rate; JSON files hold an array of objects with the
+// same keys. Dates use the YYYY-MM-DD layout.
+func LoadRates(path, base string) (*RateTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: fmt.Errorf("read rates: %w", err)}
+	}
+	defer f.Close()
+
+	var rates []ExchangeRate
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		rates, err = parseRatesJSON(f)
+	} else {
+		rates, err = parseRatesCSV(f)
+	}
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	t, err := NewRateTable(base, rates)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	return t, nil
+}
+
+func parseRatesCSV(r io.Reader) ([]ExchangeRate, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+
+	var rates []ExchangeRate
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read rates: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		date, err := time.Parse(rateDateLayout, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parse date: %w", line, err)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parse rate: %w", line, err)
+		}
+		rates = append(rates, ExchangeRate{
+			From: strings.TrimSpace(record[1]),
+			To:   strings.TrimSpace(record[2]),
+			Rate: rate,
+			Date: date,
+		})
+	}
+}
+
+func parseRatesJSON(r io.Reader) ([]ExchangeRate, error) {
+	var records []struct {
+		Date string  `json:"date"`
+		From string  `json:"from"`
+		To   string  `json:"to"`
+		Rate float64 `json:"rate"`
+	}
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("read rates: %w", err)
+	}
+	rates := make([]ExchangeRate, len(records))
+	for i, rec := range records {
+		date, err := time.Parse(rateDateLayout, rec.Date)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: parse date: %w", i, err)
+		}
+		rates[i] = ExchangeRate{From: rec.From, To: rec.To, Rate: rec.Rate, Date: date}
+	}
+	return rates, nil
+}
+
+// Rate returns the most recent rate from one currency to another dated on or
+// before the calendar date of on in its own location, using the inverse of the opposite pair or triangulating through
+// Base when there is no direct rate
+func (t *RateTable) Rate(from, to string, on time.Time) (ExchangeRate, string, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return ExchangeRate{From: from, To: to, Rate: 1, Date: on}, "", nil
+	}
+	if r, ok := t.direct(from, to, on); ok {
+		return r, "", nil
+	}
+	if t.Base != "" && from != t.Base && to != t.Base {
+		in, okIn := t.direct(from, t.Base, on)
+		out, okOut := t.direct(t.Base, to, on)
+		if okIn && okOut {
+			date := in.Date
+			if out.Date.Before(date) {
+				date = out.Date
+			}
+			return ExchangeRate{From: from, To: to, Rate: in.Rate * out.Rate, Date: date}, t.Base, nil
+		}
+	}
+	return ExchangeRate{}, "", &RateNotFoundError{From: from, To: to, On: on}
+}
+
+// direct returns the newer of the latest from/to rate and the inverse of the
+// latest to/from rate, preferring the from/to rate when both have the same date
+func (t *RateTable) direct(from, to string, on time.Time) (ExchangeRate, bool) {
+	r, ok := latest(t.rates[[2]string{from, to}], on)
+	inv, okInv := latest(t.rates[[2]string{to, from}], on)
+	if okInv && (!ok || inv.Date.After(r.Date)) {
+		return ExchangeRate{From: from, To: to, Rate: 1 / inv.Rate, Date: inv.Date}, true
+	}
+	return r, ok
+}
+
+// latest returns the last rate in date-sorted rs dated on or before the
+// calendar date of on. Rate dates are UTC days, as LoadRates parses them, so
+// on is reduced to the date it has in its own location and compared with the
+// end of that day in UTC.
+func latest(rs []ExchangeRate, on time.Time) (ExchangeRate, bool) {
+	next := time.Date(on.Year(), on.Month(), on.Day()+1, 0, 0, 0, 0, time.UTC)
+	i := sort.Search(len(rs), func(i int) bool { return !rs[i].Date.Before(next) })
+	if i == 0 {
+		return ExchangeRate{}, false
+	}
+	return rs[i-1], true
+}
+
+// Convert converts m to the currency with code to, using the rate in effect
+// on the given date and rounding to the target currency's minor unit. It can
+// be applied to a price before CalculateDiscountMoney or to its result.
+func (t *RateTable) Convert(m Money, to string, on time.Time) (Conversion, error) {
+	target, err := LookupCurrency(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+	rate, via, err := t.Rate(m.Currency.Code, target.Code, on)
+	if err != nil {
+		return Conversion{}, err
+	}
+	// Scale by whole powers of ten rather than through Float to keep
+	// amounts such as 2998.5 exact before rounding
+	amount := float64(m.Amount) * rate.Rate
+	if shift := target.Exponent - m.Currency.Exponent; shift >= 0 {
+		amount *= math.Pow10(shift)
+	} else {
+		amount /= math.Pow10(-shift)
+	}
+	return Conversion{
+		From: m,
+		To:   Money{Amount: int64(math.Round(amount)), Currency: target},
+		Rate: rate.Rate,
+		Date: rate.Date,
+		Via:  via,
+	}, nil
+}
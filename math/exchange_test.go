@@ -0,0 +1,217 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/testing/math/testutil"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(rateDateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func newTestRateTable(t *testing.T) *RateTable {
+	t.Helper()
+	data := "# date,from,to,rate\n" +
+		"2024-01-01,USD,EUR,0.90\n" +
+		"2024-02-01,USD,EUR,0.92\n" +
+		"2024-01-15,USD,JPY,150\n" +
+		"2024-01-01,GBP,USD,1.25\n"
+	table, err := LoadRates(createConfigFile(t, data), "USD")
+	if err != nil {
+		t.Fatalf("LoadRates failed: %v", err)
+	}
+	return table
+}
+
+func TestRateTable_Convert(t *testing.T) {
+	table := newTestRateTable(t)
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from     Currency
+		to       string
+		on       string
+		want     string
+		wantRate float64
+		wantDate string
+		wantVia  string
+	}{
+		{"DirectRate", 10000, USD, "EUR", "2024-01-20", "90.00 EUR", 0.90, "2024-01-01", ""},
+		{"PicksLatestRate", 10000, USD, "EUR", "2024-03-01", "92.00 EUR", 0.92, "2024-02-01", ""},
+		{"InverseRate", 9200, EUR, "USD", "2024-02-01", "100.00 USD", 1 / 0.92, "2024-02-01", ""},
+		{"ToZeroDecimalCurrency", 1999, USD, "JPY", "2024-01-20", "2999 JPY", 150, "2024-01-15", ""},
+		{"TriangulatedThroughBase", 10000, GBP, "EUR", "2024-01-20", "112.50 EUR", 1.25 * 0.90, "2024-01-01", "USD"},
+		{"SameCurrency", 500, USD, "USD", "2024-01-20", "5.00 USD", 1, "2024-01-20", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money{Amount: tt.amount, Currency: tt.from}
+			got, err := table.Convert(m, tt.to, date(tt.on))
+			if err != nil {
+				t.Fatalf("Convert(%v, %s, %s) failed: %v", m, tt.to, tt.on, err)
+			}
+			if got.To.String() != tt.want {
+				t.Errorf("Convert(%v, %s, %s) = %v, want %s", m, tt.to, tt.on, got.To, tt.want)
+			}
+			testutil.EqualFloat(t, got.Rate, tt.wantRate, 1e-9)
+			if !got.Date.Equal(date(tt.wantDate)) {
+				t.Errorf("Convert(%v, %s, %s) rate date = %s, want %s", m, tt.to, tt.on, got.Date.Format(rateDateLayout), tt.wantDate)
+			}
+			if got.Via != tt.wantVia {
+				t.Errorf("Convert(%v, %s, %s) via = %q, want %q", m, tt.to, tt.on, got.Via, tt.wantVia)
+			}
+		})
+	}
+}
+
+func TestRateTable_Rate_NewerInverse(t *testing.T) {
+	table, err := NewRateTable("", []ExchangeRate{
+		{From: "USD", To: "EUR", Rate: 0.90, Date: date("2020-01-01")},
+		{From: "EUR", To: "USD", Rate: 1.25, Date: date("2024-01-01")},
+	})
+	if err != nil {
+		t.Fatalf("NewRateTable failed: %v", err)
+	}
+
+	got, _, err := table.Rate("USD", "EUR", date("2024-06-01"))
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	testutil.EqualFloat(t, got.Rate, 1/1.25, 1e-9)
+	if !got.Date.Equal(date("2024-01-01")) {
+		t.Errorf("Rate date = %s, want 2024-01-01", got.Date.Format(rateDateLayout))
+	}
+
+	got, _, err = table.Rate("USD", "EUR", date("2021-01-01"))
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	testutil.EqualFloat(t, got.Rate, 0.90, 1e-9)
+}
+
+func TestNewRateTable_InvalidRates(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		_, err := NewRateTable("", []ExchangeRate{{From: "USD", To: "EUR", Rate: rate, Date: date("2024-01-01")}})
+		if err == nil {
+			t.Errorf("NewRateTable with rate %v should return an error", rate)
+		}
+	}
+}
+
+func TestRateTable_Rate_OnInOtherZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	table, err := NewRateTable("", []ExchangeRate{
+		{From: "USD", To: "JPY", Rate: 140, Date: date("2024-01-31")},
+		{From: "USD", To: "JPY", Rate: 150, Date: date("2024-02-01")},
+	})
+	if err != nil {
+		t.Fatalf("NewRateTable failed: %v", err)
+	}
+
+	// 08:00 on 1 February in Tokyo is still 31 January in UTC
+	got, _, err := table.Rate("USD", "JPY", time.Date(2024, 2, 1, 8, 0, 0, 0, tokyo))
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	testutil.EqualFloat(t, got.Rate, 150, 1e-9)
+}
+
+func TestRateTable_Convert_Errors(t *testing.T) {
+	table := newTestRateTable(t)
+
+	_, err := table.Convert(Money{Amount: 100, Currency: USD}, "EUR", date("2023-12-31"))
+	var notFound *RateNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Convert before first rate error = %v, want *RateNotFoundError", err)
+	}
+
+	_, err = table.Convert(Money{Amount: 100, Currency: USD}, "XYZ", date("2024-01-20"))
+	var unknown *UnknownCurrencyError
+	if !errors.As(err, &unknown) {
+		t.Errorf("Convert to unknown currency error = %v, want *UnknownCurrencyError", err)
+	}
+}
+
+func TestRateTable_ConvertAroundDiscount(t *testing.T) {
+	table := newTestRateTable(t)
+	on := date("2024-01-20")
+	price := Money{Amount: 10000, Currency: USD}
+
+	// Discount in USD, then quote in EUR
+	discounted, err := CalculateDiscountMoney(price, 10, true)
+	if err != nil {
+		t.Fatalf("CalculateDiscountMoney failed: %v", err)
+	}
+	after, err := table.Convert(discounted, "EUR", on)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	// Convert the catalogue price first, then discount in EUR
+	converted, err := table.Convert(price, "EUR", on)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	before, err := CalculateDiscountMoney(converted.To, 10, true)
+	if err != nil {
+		t.Fatalf("CalculateDiscountMoney failed: %v", err)
+	}
+
+	if after.To != before {
+		t.Errorf("discount then convert = %v, convert then discount = %v", after.To, before)
+	}
+}
+
+func TestLoadRates_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	data := `[{"date": "2024-01-01", "from": "USD", "to": "BHD", "rate": 0.376}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write rates file: %v", err)
+	}
+	table, err := LoadRates(path, "")
+	if err != nil {
+		t.Fatalf("LoadRates failed: %v", err)
+	}
+	got, err := table.Convert(Money{Amount: 1000, Currency: USD}, "BHD", date("2024-06-01"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if got.To.String() != "3.760 BHD" {
+		t.Errorf("Convert(10.00 USD, BHD) = %v, want 3.760 BHD", got.To)
+	}
+}
+
+func TestLoadRates_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantErrMsg string
+	}{
+		{"MissingFile", "", "read rates: open "},
+		{"BadDate", "01/02/2024,USD,EUR,0.9\n", "line 1: parse date"},
+		{"BadRate", "2024-01-02,USD,EUR,x\n", "line 1: parse rate"},
+		{"NonPositiveRate", "2024-01-02,USD,EUR,0\n", "invalid rate USD/EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRates(createConfigFile(t, tt.data), "USD")
+			assertError(t, err, true, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package math
+
+import (
+	"fmt"
+	"math"
+)
+
+// Guard identifies one of the checks in a PricePolicy
+type Guard int
+
+const (
+	// GuardMinPrice fires when the final price is below PricePolicy.MinPrice
+	GuardMinPrice Guard = iota
+	// GuardMaxDiscount fires when the saving exceeds PricePolicy.MaxDiscountAmount
+	GuardMaxDiscount
+	// GuardMargin fires when the final price leaves less than PricePolicy.MinMargin over Cost
+	GuardMargin
+)
+
+func (g Guard) String() string {
+	switch g {
+	case GuardMinPrice:
+		return "minimum price"
+	case GuardMaxDiscount:
+		return "maximum discount"
+	case GuardMargin:
+		return "margin"
+	default:
+		return fmt.Sprintf("Guard(%d)", int(g))
+	}
+}
+
+// GuardAction says what a PricePolicy does when a guard fires
+type GuardAction int
+
+const (
+	// GuardClamp raises the price to the lowest value every guard allows
+	GuardClamp GuardAction = iota
+	// GuardReject returns a *GuardError
+	GuardReject
+)
+
+// GuardError is returned by a rejecting PricePolicy and names the guard that fired
+type GuardError struct {
+	Guard Guard
+	Price float64
+	Limit float64
+}
+
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("%s guard: price %.2f is below the allowed %.2f", e.Guard, e.Price, e.Limit)
+}
+
+// PricePolicy holds the business rules a discounted price must satisfy.
+// A zero limit disables its guard.
+type PricePolicy struct {
+	MinPrice          float64
+	MaxDiscountAmount float64
+	// Cost and MinMargin protect the margin: the final price must be at least
+	// Cost plus MinMargin percent of Cost
+	Cost      float64
+	MinMargin float64
+	Action    GuardAction
+}
+
+// Apply checks final, the discounted form of original, against the policy.
+// Guards are checked in the order minimum price, maximum discount, margin and
+// a rejecting policy reports the first that fires. Clamping never raises a
+// price above original, so a guard whose limit is above original itself
+// returns a *GuardError even from a clamping policy.
+func (p PricePolicy) Apply(original, final float64) (float64, error) {
+	type limit struct {
+		guard Guard
+		value float64
+	}
+	var limits []limit
+	if p.MinPrice > 0 {
+		limits = append(limits, limit{GuardMinPrice, p.MinPrice})
+	}
+	if p.MaxDiscountAmount > 0 {
+		limits = append(limits, limit{GuardMaxDiscount, original - p.MaxDiscountAmount})
+	}
+	if p.Cost > 0 {
+		// Round the margin floor up so clamping never lands a fraction of a cent short
+		floor := math.Ceil(roundTo(p.Cost*(1+p.MinMargin/100)*100, 6)) / 100
+		limits = append(limits, limit{GuardMargin, floor})
+	}
+
+	clamped := final
+	for _, l := range limits {
+		if final >= l.value {
+			continue
+		}
+		if p.Action == GuardReject || l.value > original {
+			return 0, &GuardError{Guard: l.guard, Price: final, Limit: l.value}
+		}
+		clamped = max(clamped, l.value)
+	}
+	return roundTo(clamped, 2), nil
+}
+
+// CalculateDiscountWithPolicy is CalculateDiscount with the result checked against policy
+func CalculateDiscountWithPolicy(price float64, discount float64, isMember bool, policy PricePolicy) (float64, error) {
+	final, err := CalculateDiscount(price, discount, isMember)
+	if err != nil {
+		return 0, err
+	}
+	return policy.Apply(price, final)
+}
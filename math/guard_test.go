@@ -0,0 +1,82 @@
+package math
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestCalculateDiscountWithPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     float64
+		discount  float64
+		isMember  bool
+		policy    PricePolicy
+		want      float64
+		wantGuard Guard
+		wantErr   bool
+	}{
+		{"NoGuards", 100, 100, false, PricePolicy{}, 0, 0, false},
+		{"WithinLimits", 100, 10, false, PricePolicy{MinPrice: 50, MaxDiscountAmount: 20, Cost: 60, MinMargin: 10}, 90, 0, false},
+		{"ClampToMinPrice", 100, 100, false, PricePolicy{MinPrice: 5}, 5, 0, false},
+		{"ClampToMaxDiscount", 100, 50, true, PricePolicy{MaxDiscountAmount: 30}, 70, 0, false},
+		{"ClampToMargin", 100, 50, false, PricePolicy{Cost: 60, MinMargin: 10}, 66, 0, false},
+		{"ClampToHighestFloor", 100, 90, false, PricePolicy{MinPrice: 20, MaxDiscountAmount: 60}, 40, 0, false},
+		{"ClampFloorAboveOriginal", 10, 50, false, PricePolicy{MinPrice: 25}, 0, GuardMinPrice, true},
+		{"ClampMarginAboveOriginal", 10, 50, false, PricePolicy{Cost: 60, MinMargin: 10}, 0, GuardMargin, true},
+		{"ClampUndiscountedBelowCost", 10, 0, false, PricePolicy{Cost: 60}, 0, GuardMargin, true},
+		{"RejectMinPrice", 100, 100, false, PricePolicy{MinPrice: 5, Action: GuardReject}, 0, GuardMinPrice, true},
+		{"RejectMaxDiscount", 100, 50, false, PricePolicy{MaxDiscountAmount: 30, Action: GuardReject}, 0, GuardMaxDiscount, true},
+		{"RejectMargin", 100, 50, false, PricePolicy{Cost: 60, Action: GuardReject}, 0, GuardMargin, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateDiscountWithPolicy(tt.price, tt.discount, tt.isMember, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateDiscountWithPolicy(%v, %v, %v, %+v) error = %v, wantErr %v", tt.price, tt.discount, tt.isMember, tt.policy, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var guardErr *GuardError
+				if !errors.As(err, &guardErr) {
+					t.Fatalf("error type = %T, want *GuardError", err)
+				}
+				if guardErr.Guard != tt.wantGuard {
+					t.Errorf("guard = %v, want %v", guardErr.Guard, tt.wantGuard)
+				}
+				return
+			}
+			testutil.EqualFloat(t, got, tt.want, 0.001)
+		})
+	}
+}
+
+func TestPricePolicy_Apply_OriginalBelowFloor(t *testing.T) {
+	for _, action := range []GuardAction{GuardClamp, GuardReject} {
+		policy := PricePolicy{Cost: 60, MinMargin: 10, Action: action}
+		got, err := policy.Apply(10, 5)
+		var guardErr *GuardError
+		if !errors.As(err, &guardErr) || guardErr.Guard != GuardMargin {
+			t.Errorf("Apply(10, 5) with action %v = %v, %v, want a margin *GuardError", action, got, err)
+		}
+	}
+}
+
+func TestCalculateDiscountWithPolicy_InvalidInput(t *testing.T) {
+	_, err := CalculateDiscountWithPolicy(-1, 10, false, PricePolicy{})
+	var guardErr *GuardError
+	if err == nil || errors.As(err, &guardErr) {
+		t.Errorf("CalculateDiscountWithPolicy(-1, ...) error = %v, want an input error", err)
+	}
+}
+
+func TestGuard_String(t *testing.T) {
+	if got := GuardMargin.String(); got != "margin" {
+		t.Errorf("GuardMargin.String() = %q, want %q", got, "margin")
+	}
+	if got := Guard(9).String(); got != "Guard(9)" {
+		t.Errorf("Guard(9).String() = %q, want %q", got, "Guard(9)")
+	}
+}
@@ -0,0 +1,125 @@
+package math
+
+import (
+	"fmt"
+	"math"
+)
+
+// CompoundInterest returns the interest earned on principal at an annual
+// rate (e.g. 5 for 5%) compounded periodsPerYear times a year for years.
+// A periodsPerYear of zero compounds continuously.
+func CompoundInterest(principal, rate float64, periodsPerYear int, years float64) (float64, error) {
+	if principal < 0 || periodsPerYear < 0 || years < 0 {
+		return 0, fmt.Errorf("invalid input")
+	}
+	r := rate / 100
+	if periodsPerYear == 0 {
+		return principal * (math.Exp(r*years) - 1), nil
+	}
+	n := float64(periodsPerYear)
+	return principal * (math.Pow(1+r/n, n*years) - 1), nil
+}
+
+// FutureValue returns the value after nper periods of pv plus a payment pmt
+// at the end of every period, growing at rate per period (e.g. 0.5 for 0.5%).
+// Unlike spreadsheet FV, money is positive in both directions.
+func FutureValue(rate float64, nper int, pmt, pv float64) float64 {
+	r := rate / 100
+	n := float64(nper)
+	if r == 0 {
+		return pv + pmt*n
+	}
+	growth := math.Pow(1+r, n)
+	return pv*growth + pmt*(growth-1)/r
+}
+
+// PresentValue returns the value today of a payment pmt at the end of each
+// of nper periods plus a final amount fv, discounted at rate per period
+func PresentValue(rate float64, nper int, pmt, fv float64) float64 {
+	r := rate / 100
+	n := float64(nper)
+	if r == 0 {
+		return fv + pmt*n
+	}
+	discount := math.Pow(1+r, -n)
+	return fv*discount + pmt*(1-discount)/r
+}
+
+// NPV returns the net present value of cashFlows at rate per period. The
+// first cash flow is at time zero and is not discounted.
+func NPV(rate float64, cashFlows []float64) float64 {
+	r := rate / 100
+	var npv float64
+	for i, cf := range cashFlows {
+		npv += cf / math.Pow(1+r, float64(i))
+	}
+	return npv
+}
+
+// ConvergenceError is returned when an iterative solver gives up
+type ConvergenceError struct {
+	Iterations int
+	Last       float64
+}
+
+func (e *ConvergenceError) Error() string {
+	return fmt.Sprintf("no convergence after %d iterations, last estimate %v", e.Iterations, e.Last)
+}
+
+// IRROptions controls the IRR solver
+type IRROptions struct {
+	Guess         float64
+	Tolerance     float64
+	MaxIterations int
+}
+
+// DefaultIRROptions starts from 10% and stops when NPV is within a millionth
+var DefaultIRROptions = IRROptions{Guess: 10, Tolerance: 1e-6, MaxIterations: 100}
+
+// IRR returns the rate per period, as a percentage, at which the NPV of
+// cashFlows is zero. It uses Newton's method and falls back to bisection when
+// a Newton step leaves the valid range or the derivative vanishes.
+func IRR(cashFlows []float64, opts IRROptions) (float64, error) {
+	var pos, neg bool
+	for _, cf := range cashFlows {
+		pos = pos || cf > 0
+		neg = neg || cf < 0
+	}
+	if !pos || !neg {
+		return 0, fmt.Errorf("cash flows need both a positive and a negative value")
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = DefaultIRROptions.MaxIterations
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultIRROptions.Tolerance
+	}
+
+	// Work in fractional rates; rates at or below -100% are meaningless
+	lo, hi := -0.999999, 10.0
+	npv := func(r float64) float64 { return NPV(r*100, cashFlows) }
+	r := opts.Guess / 100
+	for i := 1; i <= opts.MaxIterations; i++ {
+		v := npv(r)
+		if math.Abs(v) < opts.Tolerance {
+			return r * 100, nil
+		}
+		// Keep a bracket when the signs allow it, for the bisection fallback
+		if v*npv(lo) < 0 {
+			hi = r
+		} else if v*npv(hi) < 0 {
+			lo = r
+		}
+
+		var d float64
+		for t, cf := range cashFlows {
+			d -= float64(t) * cf / math.Pow(1+r, float64(t+1))
+		}
+		next := r - v/d
+		if d == 0 || math.IsNaN(next) || next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		r = next
+	}
+	return 0, &ConvergenceError{Iterations: opts.MaxIterations, Last: r * 100}
+}
@@ -0,0 +1,101 @@
+package math
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestCompoundInterest(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      float64
+		rate           float64
+		periodsPerYear int
+		years          float64
+		want           float64
+		wantErr        bool
+	}{
+		{"Annual", 1000, 5, 1, 2, 102.5, false},
+		{"Monthly", 1000, 5, 12, 10, 647.01, false},
+		{"Continuous", 1000, 5, 0, 10, 648.72, false},
+		{"ZeroRate", 1000, 0, 12, 10, 0, false},
+		{"NegativePrincipal", -1, 5, 12, 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompoundInterest(tt.principal, tt.rate, tt.periodsPerYear, tt.years)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompoundInterest(%v, %v, %d, %v) error = %v, wantErr %v", tt.principal, tt.rate, tt.periodsPerYear, tt.years, err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				testutil.EqualFloat(t, got, tt.want, 0.01)
+			}
+		})
+	}
+}
+
+func TestFutureAndPresentValue(t *testing.T) {
+	testutil.EqualFloat(t, FutureValue(0.5, 120, 100, 0), 16387.93, 0.01)
+	testutil.EqualFloat(t, FutureValue(0, 10, 100, 50), 1050, 1e-9)
+	testutil.EqualFloat(t, FutureValue(5, 2, 0, 1000), 1102.5, 1e-9)
+
+	// A 30-year mortgage at 6% a year has a monthly payment of 599.55 per 100,000
+	testutil.EqualFloat(t, PresentValue(0.5, 360, 599.55, 0), 100000, 1)
+	testutil.EqualFloat(t, PresentValue(0, 10, 100, 50), 1050, 1e-9)
+
+	// PV and FV are inverses
+	fv := FutureValue(0.75, 48, 250, 1000)
+	testutil.EqualFloat(t, PresentValue(0.75, 48, 0, fv), PresentValue(0.75, 48, 250, 0)+1000, 1e-6)
+}
+
+func TestNPV(t *testing.T) {
+	testutil.EqualFloat(t, NPV(10, []float64{-1000, 500, 500, 500}), 243.43, 0.01)
+	testutil.EqualFloat(t, NPV(0, []float64{-1000, 500, 500, 500}), 500, 1e-9)
+	testutil.EqualFloat(t, NPV(10, nil), 0, 0)
+}
+
+func TestIRR(t *testing.T) {
+	tests := []struct {
+		name      string
+		cashFlows []float64
+		guess     float64
+		want      float64
+	}{
+		{"ThreeEqualReturns", []float64{-1000, 500, 500, 500}, 10, 23.375},
+		{"FarGuess", []float64{-1000, 500, 500, 500}, 900, 23.375},
+		{"NegativeGuess", []float64{-1000, 500, 500, 500}, -90, 23.375},
+		{"LosingInvestment", []float64{-1000, 100, 100, 100}, 10, -42.44},
+		{"BreakEven", []float64{-1000, 1000}, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultIRROptions
+			opts.Guess = tt.guess
+			got, err := IRR(tt.cashFlows, opts)
+			if err != nil {
+				t.Fatalf("IRR(%v) failed: %v", tt.cashFlows, err)
+			}
+			testutil.EqualFloat(t, got, tt.want, 0.01)
+			testutil.EqualFloat(t, NPV(got, tt.cashFlows), 0, 1e-6)
+		})
+	}
+}
+
+func TestIRR_Errors(t *testing.T) {
+	if _, err := IRR([]float64{100, 200}, DefaultIRROptions); err == nil {
+		t.Error("IRR with only positive cash flows should return an error")
+	}
+
+	_, err := IRR([]float64{-1000, 500, 500, 500}, IRROptions{Guess: 10, Tolerance: 1e-12, MaxIterations: 1})
+	var convErr *ConvergenceError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("IRR with one iteration error = %v, want *ConvergenceError", err)
+	}
+	if convErr.Iterations != 1 {
+		t.Errorf("ConvergenceError.Iterations = %d, want 1", convErr.Iterations)
+	}
+}
@@ -0,0 +1,127 @@
+package math
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// Installment is one payment of an amortization schedule
+type Installment struct {
+	Period    int
+	Payment   Money
+	Principal Money
+	Interest  Money
+	Balance   Money
+}
+
+// AmortizationSchedule is the full table of installments for a loan. The
+// payments sum exactly to TotalPayment, which is the principal plus
+// TotalInterest.
+type AmortizationSchedule struct {
+	Installments  []Installment
+	TotalPayment  Money
+	TotalInterest Money
+}
+
+// Amortize builds a schedule of equal monthly payments that repays principal,
+// for example the result of CalculateDiscountMoney, over months at an annual
+// percentage rate apr (e.g. 19.9). Interest is computed with exact decimal
+// arithmetic and rounded half away from zero to the minor unit each period;
+// the level payment is spread so that no two installments differ by more
+// than one minor unit, and the last one brings the balance to zero. Every
+// installment repays some principal, so a principal too small to spread over
+// months is an error.
+func Amortize(principal Money, apr float64, months int) (AmortizationSchedule, error) {
+	if principal.Amount <= 0 || apr < 0 || months <= 0 {
+		return AmortizationSchedule{}, fmt.Errorf("invalid input")
+	}
+
+	// Parse the shortest decimal form of apr so 19.9 means 19.9, not its binary neighbour
+	rate, ok := new(big.Rat).SetString(strconv.FormatFloat(apr, 'f', -1, 64))
+	if !ok {
+		return AmortizationSchedule{}, fmt.Errorf("invalid apr: %v", apr)
+	}
+	rate.Quo(rate, big.NewRat(1200, 1))
+
+	p := new(big.Rat).SetInt64(principal.Amount)
+	payment := new(big.Rat).Quo(p, big.NewRat(int64(months), 1))
+	if rate.Sign() != 0 {
+		// payment = P * r / (1 - (1+r)^-n) = P * r * g / (g - 1), with g = (1+r)^n
+		growth := ratPow(new(big.Rat).Add(big.NewRat(1, 1), rate), months)
+		payment.Mul(p, rate)
+		payment.Mul(payment, growth)
+		payment.Quo(payment, new(big.Rat).Sub(growth, big.NewRat(1, 1)))
+	}
+	// Pay the exact payment rounded down, plus one minor unit in the first
+	// extra installments, with extra the fewest that leave a final payment
+	// at most one unit above the rest. More extras only lower the final
+	// payment, until one of them repays the loan early.
+	base := new(big.Int).Quo(payment.Num(), payment.Denom()).Int64()
+	extra := sort.Search(months, func(k int) bool {
+		s, ok := amortize(principal, rate, base, months, k)
+		return !ok || s.Installments[months-1].Payment.Amount <= base+1
+	})
+	s, ok := amortize(principal, rate, base, months, extra)
+	if !ok {
+		return AmortizationSchedule{}, fmt.Errorf("principal %v is too small to repay in %d installments", principal, months)
+	}
+	return s, nil
+}
+
+// amortize builds the schedule for a level payment, one minor unit more in
+// the first extra installments. It reports false when an installment would
+// repay nothing or the balance would reach zero before the last.
+func amortize(principal Money, rate *big.Rat, level int64, months, extra int) (AmortizationSchedule, bool) {
+	s := AmortizationSchedule{
+		Installments:  make([]Installment, months),
+		TotalPayment:  Money{Currency: principal.Currency},
+		TotalInterest: Money{Currency: principal.Currency},
+	}
+	balance := principal.Amount
+	for i := range s.Installments {
+		interest := ratRound(new(big.Rat).Mul(new(big.Rat).SetInt64(balance), rate))
+		paid := level - interest
+		if i < extra {
+			paid++
+		}
+		if i == months-1 {
+			paid = balance
+		} else if paid >= balance {
+			return AmortizationSchedule{}, false
+		}
+		if paid <= 0 {
+			return AmortizationSchedule{}, false
+		}
+		balance -= paid
+		s.Installments[i] = Installment{
+			Period:    i + 1,
+			Payment:   Money{Amount: paid + interest, Currency: principal.Currency},
+			Principal: Money{Amount: paid, Currency: principal.Currency},
+			Interest:  Money{Amount: interest, Currency: principal.Currency},
+			Balance:   Money{Amount: balance, Currency: principal.Currency},
+		}
+		s.TotalPayment.Amount += paid + interest
+		s.TotalInterest.Amount += interest
+	}
+	return s, true
+}
+
+// ratPow returns x raised to the non-negative power n
+func ratPow(x *big.Rat, n int) *big.Rat {
+	result := big.NewRat(1, 1)
+	base := new(big.Rat).Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
+	return result
+}
+
+// ratRound rounds x half away from zero to an integer
+func ratRound(x *big.Rat) int64 {
+	return quoRound(x.Num(), x.Denom())
+}
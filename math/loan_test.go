@@ -0,0 +1,125 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestAmortize(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   Money
+		apr         float64
+		months      int
+		wantPayment int64
+		wantLast    int64
+	}{
+		{"TwelvePercentOneYear", usd(1000000), 12, 12, 88849, 88849},
+		{"ZeroInterest", usd(10000), 0, 3, 3333, 3334},
+		{"SingleInstallment", usd(10000), 12, 1, 10100, 10100},
+		{"FractionalAPR", usd(250000), 19.9, 24, 12712, 12712},
+		{"ZeroDecimalCurrency", Money{Amount: 100000, Currency: JPY}, 15, 6, 17404, 17404},
+		{"LevelRoundedUp", usd(9), 0, 6, 2, 2},
+		{"OneUnitPerMonth", usd(6), 0, 6, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Amortize(tt.principal, tt.apr, tt.months)
+			if err != nil {
+				t.Fatalf("Amortize(%v, %v, %d) failed: %v", tt.principal, tt.apr, tt.months, err)
+			}
+			if len(got.Installments) != tt.months {
+				t.Fatalf("Amortize returned %d installments, want %d", len(got.Installments), tt.months)
+			}
+			if p := got.Installments[0].Payment.Amount; p != tt.wantPayment {
+				t.Errorf("first payment = %d, want %d", p, tt.wantPayment)
+			}
+			last := got.Installments[tt.months-1]
+			if last.Payment.Amount != tt.wantLast {
+				t.Errorf("last payment = %d, want %d", last.Payment.Amount, tt.wantLast)
+			}
+			if last.Balance.Amount != 0 {
+				t.Errorf("final balance = %v, want 0", last.Balance)
+			}
+
+			var payments, principal, interest int64
+			for _, in := range got.Installments {
+				if in.Principal.Amount+in.Interest.Amount != in.Payment.Amount {
+					t.Errorf("period %d: principal %v + interest %v != payment %v", in.Period, in.Principal, in.Interest, in.Payment)
+				}
+				payments += in.Payment.Amount
+				principal += in.Principal.Amount
+				interest += in.Interest.Amount
+			}
+			if payments != got.TotalPayment.Amount || interest != got.TotalInterest.Amount {
+				t.Errorf("installments sum to %d paid, %d interest; totals are %v, %v", payments, interest, got.TotalPayment, got.TotalInterest)
+			}
+			if principal != tt.principal.Amount || got.TotalPayment.Amount != tt.principal.Amount+got.TotalInterest.Amount {
+				t.Errorf("repaid principal %d, want %d", principal, tt.principal.Amount)
+			}
+		})
+	}
+}
+
+func TestAmortize_SpreadsLeftover(t *testing.T) {
+	tests := []struct {
+		principal Money
+		apr       float64
+		months    int
+	}{
+		{usd(9), 0, 6},
+		{usd(17), 0, 12},
+		{usd(50), 12, 36},
+		{Money{Amount: 100, Currency: JPY}, 5, 24},
+	}
+
+	for _, tt := range tests {
+		got, err := Amortize(tt.principal, tt.apr, tt.months)
+		if err != nil {
+			t.Fatalf("Amortize(%v, %v, %d) failed: %v", tt.principal, tt.apr, tt.months, err)
+		}
+		lo, hi := got.Installments[0].Payment.Amount, got.Installments[0].Payment.Amount
+		for _, in := range got.Installments {
+			lo, hi = min(lo, in.Payment.Amount), max(hi, in.Payment.Amount)
+		}
+		if hi-lo > 1 {
+			t.Errorf("Amortize(%v, %v, %d) payments range from %d to %d, want at most one unit apart", tt.principal, tt.apr, tt.months, lo, hi)
+		}
+	}
+}
+
+func TestAmortize_DiscountedPrincipal(t *testing.T) {
+	price, err := CalculateDiscountMoney(usd(120000), 10, true)
+	if err != nil {
+		t.Fatalf("CalculateDiscountMoney failed: %v", err)
+	}
+	got, err := Amortize(price, 0, 4)
+	if err != nil {
+		t.Fatalf("Amortize(%v) failed: %v", price, err)
+	}
+	if got.TotalPayment != price {
+		t.Errorf("interest-free total = %v, want %v", got.TotalPayment, price)
+	}
+}
+
+func TestAmortize_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Money
+		apr       float64
+		months    int
+	}{
+		{"ZeroPrincipal", usd(0), 5, 12},
+		{"NegativeAPR", usd(100), -1, 12},
+		{"NoMonths", usd(100), 5, 0},
+		{"TooSmallToSpread", usd(5), 0, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Amortize(tt.principal, tt.apr, tt.months); err == nil {
+				t.Errorf("Amortize(%v, %v, %d) should return an error", tt.principal, tt.apr, tt.months)
+			}
+		})
+	}
+}
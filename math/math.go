@@ -138,10 +138,20 @@ func CalculateDiscount(price float64, discount float64, isMember bool) (float64,
 		return 0, fmt.Errorf("invalid input")
 	}
 
+	return roundTo(applyDiscount(price, discount, isMember), 2), nil
+}
+
+// applyDiscount returns the unrounded discounted price
+func applyDiscount(price float64, discount float64, isMember bool) float64 {
 	final := price * (1 - discount/100)
 	if isMember {
 		final *= 0.95
 	}
+	return final
+}
 
-	return math.Round(final*100) / 100, nil
+// roundTo rounds x half away from zero to the given number of decimal places
+func roundTo(x float64, places int) float64 {
+	scale := math.Pow10(places)
+	return math.Round(x*scale) / scale
 }
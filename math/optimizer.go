@@ -0,0 +1,191 @@
+package math
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxOffers bounds the search, which tries every combination of offers
+const maxOffers = 16
+
+// OfferKind says how an Offer reduces a price
+type OfferKind int
+
+const (
+	// OfferPercent takes Value percent off
+	OfferPercent OfferKind = iota
+	// OfferAmount takes a fixed Value off after all percentages
+	OfferAmount
+)
+
+// Offer is a coupon or tier discount a customer is eligible for
+type Offer struct {
+	ID    string
+	Kind  OfferKind
+	Value float64
+	// Group names a set of offers of which at most one may be used
+	Group string
+	// Exclusive offers cannot be combined with any other offer
+	Exclusive bool
+}
+
+// Candidate is one combination of offers the optimizer considered
+type Candidate struct {
+	Offers []string
+	Final  float64
+	// Rejected says why the combination is not allowed; empty when it is
+	Rejected string
+}
+
+// Choice is the combination of offers giving the lowest valid price
+type Choice struct {
+	Offers     []Offer
+	Final      float64
+	Reason     string
+	Candidates []Candidate
+}
+
+// Optimizer finds the best combination of offers for a customer
+type Optimizer struct {
+	IsMember bool
+	// Policy, when set, rejects combinations whose price it would not allow
+	Policy *PricePolicy
+}
+
+// Best tries every combination of offers and returns the one with the lowest
+// final price. Percentages compound and are applied through CalculateDiscount;
+// fixed amounts are then subtracted, never below zero. Ties go to the
+// combination using fewer offers.
+func (o Optimizer) Best(price float64, offers []Offer) (Choice, error) {
+	if len(offers) > maxOffers {
+		return Choice{}, fmt.Errorf("too many offers: %d, max %d", len(offers), maxOffers)
+	}
+	for _, of := range offers {
+		if of.Value < 0 || (of.Kind == OfferPercent && of.Value > 100) {
+			return Choice{}, fmt.Errorf("offer %q: invalid input", of.ID)
+		}
+	}
+
+	var choice Choice
+	bestMask, bestSize := -1, 0
+	for mask := 0; mask < 1<<len(offers); mask++ {
+		var combo []Offer
+		for i, of := range offers {
+			if mask&(1<<i) != 0 {
+				combo = append(combo, of)
+			}
+		}
+		c := Candidate{Offers: offerIDs(combo)}
+		final, err := o.price(price, combo)
+		var guardErr *GuardError
+		switch {
+		case errors.As(err, &guardErr):
+			c.Rejected = guardErr.Error()
+		case err != nil:
+			return Choice{}, err
+		default:
+			c.Final = final
+			c.Rejected = conflict(combo)
+		}
+		choice.Candidates = append(choice.Candidates, c)
+
+		if c.Rejected != "" {
+			continue
+		}
+		if bestMask < 0 || final < choice.Final || (final == choice.Final && len(combo) < bestSize) {
+			bestMask, bestSize = mask, len(combo)
+			choice.Offers, choice.Final = combo, final
+		}
+	}
+	if bestMask < 0 {
+		return Choice{}, fmt.Errorf("no valid combination of offers")
+	}
+	choice.Reason = o.reason(choice)
+	return choice, nil
+}
+
+func (o Optimizer) price(price float64, combo []Offer) (float64, error) {
+	remaining := 1.0
+	var amount float64
+	for _, of := range combo {
+		if of.Kind == OfferPercent {
+			remaining *= 1 - of.Value/100
+		} else {
+			amount += of.Value
+		}
+	}
+	final, err := CalculateDiscount(price, 100*(1-remaining), o.IsMember)
+	if err != nil {
+		return 0, err
+	}
+	final = roundTo(max(final-amount, 0), 2)
+	if o.Policy != nil {
+		policy := *o.Policy
+		policy.Action = GuardReject
+		return policy.Apply(price, final)
+	}
+	return final, nil
+}
+
+// conflict reports why combo breaks an exclusivity rule, or "" if it does not
+func conflict(combo []Offer) string {
+	groups := make(map[string]string)
+	for _, of := range combo {
+		if of.Exclusive && len(combo) > 1 {
+			return fmt.Sprintf("%s cannot be combined with other offers", of.ID)
+		}
+		if of.Group == "" {
+			continue
+		}
+		if other, ok := groups[of.Group]; ok {
+			return fmt.Sprintf("%s and %s are both in group %q", other, of.ID, of.Group)
+		}
+		groups[of.Group] = of.ID
+	}
+	return ""
+}
+
+func (o Optimizer) reason(choice Choice) string {
+	name := "no offers"
+	if len(choice.Offers) > 0 {
+		name = strings.Join(offerIDs(choice.Offers), " + ")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s gives the lowest price, %.2f", name, choice.Final)
+
+	var rejected []string
+	runnerUp := -1
+	for i, c := range choice.Candidates {
+		if c.Rejected != "" {
+			rejected = append(rejected, c.Rejected)
+			continue
+		}
+		if strings.Join(c.Offers, "+") == strings.Join(offerIDs(choice.Offers), "+") {
+			continue
+		}
+		if runnerUp < 0 || c.Final < choice.Candidates[runnerUp].Final {
+			runnerUp = i
+		}
+	}
+	if runnerUp >= 0 {
+		r := choice.Candidates[runnerUp]
+		other := "no offers"
+		if len(r.Offers) > 0 {
+			other = strings.Join(r.Offers, " + ")
+		}
+		fmt.Fprintf(&b, "; next best is %s at %.2f", other, r.Final)
+	}
+	if len(rejected) > 0 {
+		fmt.Fprintf(&b, "; %d combinations not allowed, e.g. %s", len(rejected), rejected[0])
+	}
+	return b.String()
+}
+
+func offerIDs(offers []Offer) []string {
+	ids := make([]string, len(offers))
+	for i, of := range offers {
+		ids[i] = of.ID
+	}
+	return ids
+}
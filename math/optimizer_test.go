@@ -0,0 +1,89 @@
+package math
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestOptimizer_Best(t *testing.T) {
+	save10 := Offer{ID: "SAVE10", Kind: OfferPercent, Value: 10, Group: "coupon"}
+	save20 := Offer{ID: "SAVE20", Kind: OfferPercent, Value: 20, Group: "coupon"}
+	fiveOff := Offer{ID: "5OFF", Kind: OfferAmount, Value: 5}
+	flash := Offer{ID: "FLASH", Kind: OfferPercent, Value: 30, Exclusive: true}
+
+	tests := []struct {
+		name      string
+		optimizer Optimizer
+		price     float64
+		offers    []Offer
+		wantIDs   []string
+		wantFinal float64
+	}{
+		{"NoOffers", Optimizer{}, 100, nil, []string{}, 100},
+		{"MemberNoOffers", Optimizer{IsMember: true}, 100, nil, []string{}, 95},
+		{"OneCouponPerGroup", Optimizer{}, 100, []Offer{save10, save20}, []string{"SAVE20"}, 80},
+		{"StackCouponAndAmount", Optimizer{}, 100, []Offer{save10, save20, fiveOff}, []string{"SAVE20", "5OFF"}, 75},
+		{"ExclusiveBeatsStack", Optimizer{}, 100, []Offer{save10, fiveOff, flash}, []string{"FLASH"}, 70},
+		{"StackBeatsExclusive", Optimizer{}, 100, []Offer{save20, fiveOff, Offer{ID: "FLASH", Kind: OfferPercent, Value: 22, Exclusive: true}}, []string{"SAVE20", "5OFF"}, 75},
+		{"AmountBeatsPercentOnCheapItem", Optimizer{}, 10, []Offer{{ID: "PCT", Kind: OfferPercent, Value: 20, Group: "g"}, {ID: "AMT", Kind: OfferAmount, Value: 5, Group: "g"}}, []string{"AMT"}, 5},
+		{"PolicyRejectsDeepestCombination", Optimizer{Policy: &PricePolicy{MinPrice: 78}}, 100, []Offer{save20, fiveOff, flash}, []string{"SAVE20"}, 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.optimizer.Best(tt.price, tt.offers)
+			if err != nil {
+				t.Fatalf("Best(%v, %v) failed: %v", tt.price, tt.offers, err)
+			}
+			if ids := offerIDs(got.Offers); !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Best(%v) chose %v, want %v", tt.price, ids, tt.wantIDs)
+			}
+			testutil.EqualFloat(t, got.Final, tt.wantFinal, 0.001)
+			if len(got.Candidates) != 1<<len(tt.offers) {
+				t.Errorf("Best(%v) considered %d combinations, want %d", tt.price, len(got.Candidates), 1<<len(tt.offers))
+			}
+			if got.Reason == "" {
+				t.Errorf("Best(%v) returned no reason", tt.price)
+			}
+		})
+	}
+}
+
+func TestOptimizer_Reason(t *testing.T) {
+	offers := []Offer{
+		{ID: "SAVE10", Kind: OfferPercent, Value: 10, Group: "coupon"},
+		{ID: "SAVE20", Kind: OfferPercent, Value: 20, Group: "coupon"},
+	}
+	got, err := Optimizer{}.Best(100, offers)
+	if err != nil {
+		t.Fatalf("Best failed: %v", err)
+	}
+	for _, want := range []string{"SAVE20 gives the lowest price, 80.00", "next best is SAVE10 at 90.00", `SAVE10 and SAVE20 are both in group "coupon"`} {
+		if !strings.Contains(got.Reason, want) {
+			t.Errorf("Reason = %q, want to contain %q", got.Reason, want)
+		}
+	}
+}
+
+func TestOptimizer_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  float64
+		offers []Offer
+	}{
+		{"InvalidPrice", -1, nil},
+		{"InvalidPercent", 100, []Offer{{ID: "BAD", Kind: OfferPercent, Value: 120}}},
+		{"TooManyOffers", 100, make([]Offer, maxOffers+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (Optimizer{}).Best(tt.price, tt.offers); err == nil {
+				t.Errorf("Best(%v, %d offers) should return an error", tt.price, len(tt.offers))
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package math
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ending is a price ending: prices whose amount modulo Modulus equals
+// Remainder, both in minor units. {100, 99} is the .99 ending for USD.
+type Ending struct {
+	Modulus   int64
+	Remainder int64
+}
+
+// DecimalEnding returns the ending with the given fraction of a major unit,
+// e.g. 0.95 for .95
+func DecimalEnding(c Currency, fraction float64) Ending {
+	unit := int64(math.Pow10(c.Exponent))
+	return Ending{Modulus: unit, Remainder: int64(math.Round(fraction * float64(unit)))}
+}
+
+// WholeMinusOne returns the ending one major unit below a multiple of step
+// major units, e.g. 9, 19, 29 for a step of 10
+func WholeMinusOne(c Currency, step int64) Ending {
+	unit := int64(math.Pow10(c.Exponent))
+	return Ending{Modulus: step * unit, Remainder: (step - 1) * unit}
+}
+
+// SnapMode says which way a price moves to reach an allowed ending
+type SnapMode int
+
+const (
+	// SnapDown moves to the highest allowed price at or below the price
+	SnapDown SnapMode = iota
+	// SnapUp moves to the lowest allowed price at or above the price
+	SnapUp
+	// SnapNearest moves to the closest allowed price, preferring the lower on a tie
+	SnapNearest
+)
+
+// PriceEndingRules lists the allowed endings per currency code
+type PriceEndingRules map[string][]Ending
+
+// DefaultPriceEndings are common merchandising endings for a few currencies
+var DefaultPriceEndings = PriceEndingRules{
+	"USD": {DecimalEnding(USD, 0.99), DecimalEnding(USD, 0.95)},
+	"EUR": {DecimalEnding(EUR, 0.99), DecimalEnding(EUR, 0.95)},
+	"GBP": {DecimalEnding(GBP, 0.99), DecimalEnding(GBP, 0.95)},
+	"JPY": {{Modulus: 100, Remainder: 80}, {Modulus: 100, Remainder: 99}},
+	"BHD": {DecimalEnding(BHD, 0.99), DecimalEnding(BHD, 0.95)},
+	"KWD": {DecimalEnding(KWD, 0.99), DecimalEnding(KWD, 0.95)},
+}
+
+// PriceEndingError is returned when no allowed ending fits a price
+type PriceEndingError struct {
+	Price    Money
+	Original Money
+}
+
+func (e *PriceEndingError) Error() string {
+	return fmt.Sprintf("no allowed price ending for %v at or below %v", e.Price, e.Original)
+}
+
+// SnapPrice moves price, typically the result of CalculateDiscountMoney, to
+// one of the allowed endings. The snapped price never exceeds original, the
+// undiscounted price: a price above original is rejected, and when snapping
+// up would exceed original the price snaps down instead.
+func SnapPrice(price, original Money, endings []Ending, mode SnapMode) (Money, error) {
+	if price.Currency != original.Currency {
+		return Money{}, &CurrencyMismatchError{Want: original.Currency.Code, Got: price.Currency.Code}
+	}
+	if price.Amount < 0 {
+		return Money{}, fmt.Errorf("invalid input")
+	}
+	if price.Amount > original.Amount {
+		return Money{}, fmt.Errorf("price %v is above the original %v", price, original)
+	}
+
+	down, up := int64(-1), int64(-1)
+	for _, e := range endings {
+		if e.Modulus <= 0 || e.Remainder < 0 || e.Remainder >= e.Modulus {
+			return Money{}, fmt.Errorf("invalid ending: %+v", e)
+		}
+		d := price.Amount - mod(price.Amount-e.Remainder, e.Modulus)
+		u := d
+		if u < price.Amount {
+			u += e.Modulus
+		}
+		if d >= 0 && d > down {
+			down = d
+		}
+		if u <= original.Amount && (up < 0 || u < up) {
+			up = u
+		}
+	}
+
+	snapped := down
+	switch mode {
+	case SnapUp:
+		if up >= 0 {
+			snapped = up
+		}
+	case SnapNearest:
+		if up >= 0 && (down < 0 || up-price.Amount < price.Amount-down) {
+			snapped = up
+		}
+	}
+	if snapped < 0 {
+		return Money{}, &PriceEndingError{Price: price, Original: original}
+	}
+	return Money{Amount: snapped, Currency: price.Currency}, nil
+}
+
+// Snap is SnapPrice using the endings for the price's currency
+func (r PriceEndingRules) Snap(price, original Money, mode SnapMode) (Money, error) {
+	endings, ok := r[price.Currency.Code]
+	if !ok {
+		return Money{}, &UnknownCurrencyError{Code: price.Currency.Code}
+	}
+	return SnapPrice(price, original, endings, mode)
+}
+
+// mod returns a modulo m in [0, m)
+func mod(a, m int64) int64 {
+	return ((a % m) + m) % m
+}
@@ -0,0 +1,121 @@
+package math
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSnapPrice(t *testing.T) {
+	cents := []Ending{DecimalEnding(USD, 0.99), DecimalEnding(USD, 0.95)}
+
+	tests := []struct {
+		name     string
+		price    int64
+		original int64
+		endings  []Ending
+		mode     SnapMode
+		want     int64
+	}{
+		{"DownToNinetyFive", 1797, 2000, cents, SnapDown, 1795},
+		{"DownToPreviousNinetyNine", 1790, 2000, cents, SnapDown, 1699},
+		{"UpToNinetyNine", 1797, 2000, cents, SnapUp, 1799},
+		{"AlreadyOnEnding", 1799, 2000, cents, SnapNearest, 1799},
+		{"NearestPrefersCloser", 1797, 2000, cents, SnapNearest, 1795},
+		{"NearestTiePrefersLower", 1749, 2000, []Ending{DecimalEnding(USD, 0.99)}, SnapNearest, 1699},
+		{"UpCappedByOriginal", 1997, 1998, cents, SnapUp, 1995},
+		{"WholeMinusOne", 2350, 5000, []Ending{WholeMinusOne(USD, 10)}, SnapNearest, 1900},
+		{"WholeMinusOneUp", 2350, 5000, []Ending{WholeMinusOne(USD, 10)}, SnapUp, 2900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SnapPrice(usd(tt.price), usd(tt.original), tt.endings, tt.mode)
+			if err != nil {
+				t.Fatalf("SnapPrice(%d, %d) failed: %v", tt.price, tt.original, err)
+			}
+			if got.Amount != tt.want {
+				t.Errorf("SnapPrice(%d, %d) = %v, want %d", tt.price, tt.original, got, tt.want)
+			}
+			if got.Amount > tt.original {
+				t.Errorf("SnapPrice(%d, %d) = %v exceeds the original price", tt.price, tt.original, got)
+			}
+		})
+	}
+}
+
+func TestPriceEndingRules_Snap(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    Money
+		original Money
+		mode     SnapMode
+		want     string
+	}{
+		{"USD", usd(8550), usd(10000), SnapDown, "84.99 USD"},
+		{"JPY", Money{Amount: 1234, Currency: JPY}, Money{Amount: 1500, Currency: JPY}, SnapNearest, "1199 JPY"},
+		{"JPYUp", Money{Amount: 1234, Currency: JPY}, Money{Amount: 1500, Currency: JPY}, SnapUp, "1280 JPY"},
+		{"KWD", Money{Amount: 12340, Currency: KWD}, Money{Amount: 15000, Currency: KWD}, SnapUp, "12.950 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultPriceEndings.Snap(tt.price, tt.original, tt.mode)
+			if err != nil {
+				t.Fatalf("Snap(%v) failed: %v", tt.price, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Snap(%v) = %v, want %s", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapPrice_AfterDiscountNeverExceedsOriginal(t *testing.T) {
+	for amount := int64(0); amount <= 3000; amount += 7 {
+		original := usd(amount)
+		for _, discount := range []float64{0, 5, 10, 33} {
+			price, err := CalculateDiscountMoney(original, discount, true)
+			if err != nil {
+				t.Fatalf("CalculateDiscountMoney failed: %v", err)
+			}
+			got, err := DefaultPriceEndings.Snap(price, original, SnapUp)
+			var endingErr *PriceEndingError
+			if errors.As(err, &endingErr) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Snap(%v) failed: %v", price, err)
+			}
+			if got.Amount > original.Amount {
+				t.Fatalf("Snap(%v) = %v exceeds original %v", price, got, original)
+			}
+		}
+	}
+}
+
+func TestSnapPrice_Errors(t *testing.T) {
+	var endingErr *PriceEndingError
+	if _, err := SnapPrice(usd(50), usd(60), []Ending{DecimalEnding(USD, 0.99)}, SnapUp); !errors.As(err, &endingErr) {
+		t.Errorf("SnapPrice(0.50, 0.60) error = %v, want *PriceEndingError", err)
+	}
+
+	var mismatch *CurrencyMismatchError
+	if _, err := SnapPrice(usd(50), Money{Amount: 60, Currency: EUR}, nil, SnapDown); !errors.As(err, &mismatch) {
+		t.Errorf("SnapPrice across currencies error = %v, want *CurrencyMismatchError", err)
+	}
+
+	for _, mode := range []SnapMode{SnapDown, SnapUp, SnapNearest} {
+		if got, err := SnapPrice(usd(12000), usd(10000), DefaultPriceEndings["USD"], mode); err == nil {
+			t.Errorf("SnapPrice(120.00, 100.00, %v) = %v, want an error for a price above the original", mode, got)
+		}
+	}
+
+	if _, err := SnapPrice(usd(50), usd(60), []Ending{{Modulus: 0}}, SnapDown); err == nil {
+		t.Error("SnapPrice with a zero modulus should return an error")
+	}
+
+	var unknown *UnknownCurrencyError
+	if _, err := DefaultPriceEndings.Snap(Money{Amount: 1, Currency: CHF}, Money{Amount: 1, Currency: CHF}, SnapDown); !errors.As(err, &unknown) {
+		t.Errorf("Snap in CHF error = %v, want *UnknownCurrencyError", err)
+	}
+}
@@ -0,0 +1,131 @@
+package math
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Clock tells the current time; tests pass a FixedClock to pin it
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock that reads the system time
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a Clock that always returns the same instant
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+// WeeklyWindow is a recurring period on some days of the week. Start and End
+// are wall-clock times of day, as offsets from midnight, so 17 hours means
+// 17:00 even on a daylight saving change day; an End at or before Start runs
+// past midnight into the following day.
+type WeeklyWindow struct {
+	Days  []time.Weekday
+	Start time.Duration
+	End   time.Duration
+}
+
+func (w WeeklyWindow) contains(t time.Time) bool {
+	// Use the clock reading rather than time elapsed since midnight, which
+	// is an hour off after a daylight saving change
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if w.Start < w.End {
+		return slices.Contains(w.Days, t.Weekday()) && offset >= w.Start && offset < w.End
+	}
+	// Overnight window: the late part of a listed day or the early part of the day after
+	yesterday := (t.Weekday() + 6) % 7
+	return (slices.Contains(w.Days, t.Weekday()) && offset >= w.Start) ||
+		(slices.Contains(w.Days, yesterday) && offset < w.End)
+}
+
+// Promotion adds Discount percentage points while it is active. It is active
+// between From (inclusive) and Until (exclusive), either of which may be zero
+// for an open range, and, when Windows is set, only inside one of them.
+// Windows are evaluated in Location, or UTC when it is nil.
+type Promotion struct {
+	Name     string
+	Discount float64
+	From     time.Time
+	Until    time.Time
+	Windows  []WeeklyWindow
+	Location *time.Location
+}
+
+// ActiveAt reports whether the promotion applies at t
+func (p Promotion) ActiveAt(t time.Time) bool {
+	if !p.From.IsZero() && t.Before(p.From) {
+		return false
+	}
+	if !p.Until.IsZero() && !t.Before(p.Until) {
+		return false
+	}
+	if len(p.Windows) == 0 {
+		return true
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	for _, w := range p.Windows {
+		if w.contains(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule holds the promotions that adjust prices at the time told by Clock
+type Schedule struct {
+	Clock      Clock
+	Promotions []Promotion
+}
+
+// NewSchedule returns a schedule of promotions driven by clock
+func NewSchedule(clock Clock, promotions ...Promotion) *Schedule {
+	return &Schedule{Clock: clock, Promotions: promotions}
+}
+
+// Active returns the promotions that apply now
+func (s *Schedule) Active() []Promotion {
+	now := s.Clock.Now()
+	var active []Promotion
+	for _, p := range s.Promotions {
+		if p.ActiveAt(now) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
+// Discount adds the active promotions to base, capping the result at 100
+func (s *Schedule) Discount(base float64) (float64, []Promotion) {
+	active := s.Active()
+	discount := base
+	for _, p := range active {
+		discount += p.Discount
+	}
+	return min(discount, 100), active
+}
+
+// CalculateDiscount is CalculateDiscount with the active promotions added to discount
+func (s *Schedule) CalculateDiscount(price float64, discount float64, isMember bool) (float64, error) {
+	if discount < 0 || discount > 100 {
+		return 0, fmt.Errorf("invalid input")
+	}
+	adjusted, _ := s.Discount(discount)
+	return CalculateDiscount(price, adjusted, isMember)
+}
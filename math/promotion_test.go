@@ -0,0 +1,137 @@
+package math
+
+import (
+	"testing"
+	"time"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestPromotion_ActiveAt(t *testing.T) {
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	happyHour := Promotion{
+		Name:     "HappyHour",
+		Discount: 15,
+		Windows:  []WeeklyWindow{{Days: []time.Weekday{time.Friday}, Start: 17 * time.Hour, End: 19 * time.Hour}},
+		Location: lagos,
+	}
+	lateNight := Promotion{
+		Name:     "LateNight",
+		Discount: 5,
+		Windows:  []WeeklyWindow{{Days: []time.Weekday{time.Saturday}, Start: 22 * time.Hour, End: 2 * time.Hour}},
+	}
+	blackFriday := Promotion{
+		Name:     "BlackFriday",
+		Discount: 30,
+		From:     time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC),
+		Until:    time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name  string
+		promo Promotion
+		at    time.Time
+		want  bool
+	}{
+		// 2024-11-29 is a Friday; Lagos is UTC+1
+		{"HappyHourInLocalTime", happyHour, time.Date(2024, 11, 29, 16, 30, 0, 0, time.UTC), true},
+		{"HappyHourBeforeLocalStart", happyHour, time.Date(2024, 11, 29, 15, 30, 0, 0, time.UTC), false},
+		{"HappyHourEndIsExclusive", happyHour, time.Date(2024, 11, 29, 18, 0, 0, 0, time.UTC), false},
+		{"HappyHourWrongDay", happyHour, time.Date(2024, 11, 28, 16, 30, 0, 0, time.UTC), false},
+		{"OvernightBeforeMidnight", lateNight, time.Date(2024, 11, 30, 23, 0, 0, 0, time.UTC), true},
+		{"OvernightAfterMidnight", lateNight, time.Date(2024, 12, 1, 1, 0, 0, 0, time.UTC), true},
+		{"OvernightEnded", lateNight, time.Date(2024, 12, 1, 2, 0, 0, 0, time.UTC), false},
+		{"DateRangeStart", blackFriday, time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC), true},
+		{"DateRangeUntilIsExclusive", blackFriday, time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promo.ActiveAt(tt.at); got != tt.want {
+				t.Errorf("%s.ActiveAt(%v) = %v, want %v", tt.promo.Name, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotion_ActiveAt_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	// DST starts at 02:00 on Sunday 10 March 2024, so 17:00 local is only
+	// 16 hours after midnight
+	evening := Promotion{
+		Name:     "Evening",
+		Discount: 10,
+		Windows:  []WeeklyWindow{{Days: []time.Weekday{time.Sunday}, Start: 17 * time.Hour, End: 19 * time.Hour}},
+		Location: ny,
+	}
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2024, 3, 10, 16, 30, 0, 0, ny), false},
+		{time.Date(2024, 3, 10, 17, 0, 0, 0, ny), true},
+		{time.Date(2024, 3, 10, 17, 30, 0, 0, ny), true},
+		{time.Date(2024, 3, 10, 19, 0, 0, 0, ny), false},
+		{time.Date(2024, 3, 10, 19, 30, 0, 0, ny), false},
+		// DST ends on 3 November 2024, making the day 25 hours long
+		{time.Date(2024, 11, 3, 18, 30, 0, 0, ny), true},
+		{time.Date(2024, 11, 3, 16, 30, 0, 0, ny), false},
+	}
+
+	for _, tt := range tests {
+		if got := evening.ActiveAt(tt.at); got != tt.want {
+			t.Errorf("ActiveAt(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_CalculateDiscount(t *testing.T) {
+	weekend := Promotion{
+		Name:     "Weekend",
+		Discount: 10,
+		Windows:  []WeeklyWindow{{Days: []time.Weekday{time.Saturday, time.Sunday}, Start: 0, End: 24 * time.Hour}},
+	}
+	clearance := Promotion{Name: "Clearance", Discount: 95}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		promos     []Promotion
+		discount   float64
+		want       float64
+		wantActive int
+	}{
+		{"Weekday", time.Date(2024, 12, 4, 12, 0, 0, 0, time.UTC), []Promotion{weekend}, 10, 90, 0},
+		{"Weekend", time.Date(2024, 12, 7, 12, 0, 0, 0, time.UTC), []Promotion{weekend}, 10, 80, 1},
+		{"CappedAt100", time.Date(2024, 12, 7, 12, 0, 0, 0, time.UTC), []Promotion{weekend, clearance}, 10, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchedule(FixedClock{Time: tt.now}, tt.promos...)
+			if got := len(s.Active()); got != tt.wantActive {
+				t.Errorf("Active() returned %d promotions, want %d", got, tt.wantActive)
+			}
+			got, err := s.CalculateDiscount(100, tt.discount, false)
+			if err != nil {
+				t.Fatalf("CalculateDiscount(100, %v, false) failed: %v", tt.discount, err)
+			}
+			testutil.EqualFloat(t, got, tt.want, 0.001)
+		})
+	}
+}
+
+func TestSchedule_InvalidDiscount(t *testing.T) {
+	s := NewSchedule(SystemClock{})
+	if _, err := s.CalculateDiscount(100, 150, false); err == nil {
+		t.Error("CalculateDiscount(100, 150, false) should return an error")
+	}
+}
@@ -0,0 +1,107 @@
+package math
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ProrationUnit is the granularity used to measure a partial billing period
+type ProrationUnit int
+
+const (
+	// ProrateByDays counts whole calendar days in the period's time zone
+	ProrateByDays ProrationUnit = iota
+	// ProrateBySeconds counts elapsed seconds
+	ProrateBySeconds
+)
+
+// BillingPeriod is the half-open interval [Start, End) a price is charged for
+type BillingPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// PlanChange is the result of switching plans part way through a period.
+// Net is Charge minus Credit; it is negative when a downgrade leaves the
+// customer in credit.
+type PlanChange struct {
+	Credit Money
+	Charge Money
+	Net    Money
+}
+
+// units returns the length of [from, to) in unit, counting days in loc
+func (u ProrationUnit) units(from, to time.Time, loc *time.Location) int64 {
+	if u == ProrateBySeconds {
+		return int64(to.Sub(from) / time.Second)
+	}
+	// Count calendar days so that DST changes do not shorten or lengthen a day
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int64(day(to).Sub(day(from)).Hours() / 24)
+}
+
+// Prorate returns the share of price, charged for period, that covers
+// [from, to). Days are counted in the time zone of period.Start whatever the
+// zones of from and to. The result is rounded half away from zero to the
+// minor unit.
+func Prorate(price Money, period BillingPeriod, from, to time.Time, unit ProrationUnit) (Money, error) {
+	if !period.Start.Before(period.End) {
+		return Money{}, fmt.Errorf("invalid billing period: %v to %v", period.Start, period.End)
+	}
+	if from.Before(period.Start) || to.After(period.End) || to.Before(from) {
+		return Money{}, fmt.Errorf("interval %v to %v is outside the billing period", from, to)
+	}
+	loc := period.Start.Location()
+	total := unit.units(period.Start, period.End, loc)
+	if total <= 0 {
+		return Money{}, fmt.Errorf("billing period is shorter than one unit")
+	}
+	used := unit.units(from, to, loc)
+	return Money{Amount: mulDivRound(price.Amount, used, total), Currency: price.Currency}, nil
+}
+
+// ChangePlan credits the unused part of oldPrice and charges the same part of
+// newPrice when the plan changes at the given time
+func ChangePlan(oldPrice, newPrice Money, period BillingPeriod, at time.Time, unit ProrationUnit) (PlanChange, error) {
+	if oldPrice.Currency != newPrice.Currency {
+		return PlanChange{}, &CurrencyMismatchError{Want: oldPrice.Currency.Code, Got: newPrice.Currency.Code}
+	}
+	credit, err := Prorate(oldPrice, period, at, period.End, unit)
+	if err != nil {
+		return PlanChange{}, err
+	}
+	charge, err := Prorate(newPrice, period, at, period.End, unit)
+	if err != nil {
+		return PlanChange{}, err
+	}
+	net, err := charge.Sub(credit)
+	if err != nil {
+		return PlanChange{}, err
+	}
+	return PlanChange{Credit: credit, Charge: charge, Net: net}, nil
+}
+
+// mulDivRound returns a*num/den rounded half away from zero, without overflow
+// in the intermediate product
+func mulDivRound(a, num, den int64) int64 {
+	return quoRound(new(big.Int).Mul(big.NewInt(a), big.NewInt(num)), big.NewInt(den))
+}
+
+// quoRound returns num/den rounded half away from zero
+func quoRound(num, den *big.Int) int64 {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	// QuoRem truncates toward zero, so round the magnitude up when the
+	// remainder is at least half the divisor
+	if new(big.Int).Abs(new(big.Int).Lsh(r, 1)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
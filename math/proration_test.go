@@ -0,0 +1,159 @@
+package math
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestProrate(t *testing.T) {
+	january := BillingPeriod{Start: day(2024, 1, 1), End: day(2024, 2, 1)}
+	april := BillingPeriod{Start: day(2024, 4, 1), End: day(2024, 5, 1)}
+
+	tests := []struct {
+		name   string
+		price  Money
+		period BillingPeriod
+		from   time.Time
+		to     time.Time
+		unit   ProrationUnit
+		want   string
+	}{
+		{"WholePeriod", usd(3100), january, january.Start, january.End, ProrateByDays, "31.00 USD"},
+		{"RemainingDays", usd(3100), january, day(2024, 1, 11), january.End, ProrateByDays, "21.00 USD"},
+		{"RoundsHalfUp", usd(1000), january, day(2024, 1, 1), day(2024, 1, 11), ProrateByDays, "3.23 USD"},
+		{"ZeroDecimalCurrency", Money{Amount: 1000, Currency: JPY}, january, day(2024, 1, 1), day(2024, 1, 11), ProrateByDays, "323 JPY"},
+		{"ThreeDecimalCurrency", Money{Amount: 10000, Currency: KWD}, april, day(2024, 4, 1), day(2024, 4, 8), ProrateByDays, "2.333 KWD"},
+		{"BySeconds", usd(10000), april, april.Start, april.Start.Add(12 * time.Hour), ProrateBySeconds, "1.67 USD"},
+		{"EmptyInterval", usd(10000), april, day(2024, 4, 5), day(2024, 4, 5), ProrateByDays, "0.00 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Prorate(tt.price, tt.period, tt.from, tt.to, tt.unit)
+			if err != nil {
+				t.Fatalf("Prorate(%v) failed: %v", tt.price, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Prorate(%v, %v to %v) = %v, want %s", tt.price, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProrate_DaysIgnoreDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	// DST starts on 10 March 2024, so this 31-day period is 743 hours long
+	march := BillingPeriod{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, ny), End: time.Date(2024, 4, 1, 0, 0, 0, 0, ny)}
+	got, err := Prorate(usd(3100), march, time.Date(2024, 3, 17, 0, 0, 0, 0, ny), march.End, ProrateByDays)
+	if err != nil {
+		t.Fatalf("Prorate failed: %v", err)
+	}
+	if got.Amount != 1500 {
+		t.Errorf("Prorate(31.00 USD, 15 of 31 days) = %v, want 15.00 USD", got)
+	}
+}
+
+func TestProrate_DaysInPeriodZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	march := BillingPeriod{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, ny), End: time.Date(2024, 4, 1, 0, 0, 0, 0, ny)}
+	// 23:00 on 16 March in New York is already 17 March in UTC
+	from := time.Date(2024, 3, 16, 23, 0, 0, 0, ny)
+	for _, f := range []time.Time{from, from.UTC()} {
+		got, err := Prorate(usd(3100), march, f, march.End, ProrateByDays)
+		if err != nil {
+			t.Fatalf("Prorate failed: %v", err)
+		}
+		if got.Amount != 1600 {
+			t.Errorf("Prorate(31.00 USD) from %v = %v, want 16.00 USD", f, got)
+		}
+	}
+}
+
+func TestProrate_Errors(t *testing.T) {
+	april := BillingPeriod{Start: day(2024, 4, 1), End: day(2024, 5, 1)}
+	tests := []struct {
+		name   string
+		period BillingPeriod
+		from   time.Time
+		to     time.Time
+	}{
+		{"EmptyPeriod", BillingPeriod{Start: april.Start, End: april.Start}, april.Start, april.Start},
+		{"StartsBeforePeriod", april, day(2024, 3, 31), april.End},
+		{"EndsAfterPeriod", april, april.Start, day(2024, 5, 2)},
+		{"Backwards", april, day(2024, 4, 10), day(2024, 4, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Prorate(usd(100), tt.period, tt.from, tt.to, ProrateByDays); err == nil {
+				t.Error("Prorate should return an error")
+			}
+		})
+	}
+}
+
+func TestChangePlan(t *testing.T) {
+	april := BillingPeriod{Start: day(2024, 4, 1), End: day(2024, 5, 1)}
+	at := day(2024, 4, 16)
+
+	tests := []struct {
+		name       string
+		oldPrice   Money
+		newPrice   Money
+		wantCredit int64
+		wantCharge int64
+		wantNet    int64
+	}{
+		{"Upgrade", usd(3000), usd(6000), 1500, 3000, 1500},
+		{"Downgrade", usd(6000), usd(3000), 3000, 1500, -1500},
+		{"DiscountedPlan", usd(3000), usd(2565), 1500, 1283, -217},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ChangePlan(tt.oldPrice, tt.newPrice, april, at, ProrateByDays)
+			if err != nil {
+				t.Fatalf("ChangePlan(%v, %v) failed: %v", tt.oldPrice, tt.newPrice, err)
+			}
+			if got.Credit.Amount != tt.wantCredit || got.Charge.Amount != tt.wantCharge || got.Net.Amount != tt.wantNet {
+				t.Errorf("ChangePlan(%v, %v) = credit %v, charge %v, net %v, want %d, %d, %d",
+					tt.oldPrice, tt.newPrice, got.Credit, got.Charge, got.Net, tt.wantCredit, tt.wantCharge, tt.wantNet)
+			}
+		})
+	}
+
+	_, err := ChangePlan(usd(100), Money{Amount: 100, Currency: EUR}, april, at, ProrateByDays)
+	var mismatch *CurrencyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Errorf("ChangePlan across currencies error = %v, want *CurrencyMismatchError", err)
+	}
+}
+
+func TestMulDivRound(t *testing.T) {
+	tests := []struct {
+		a, num, den int64
+		want        int64
+	}{
+		{5, 1, 2, 3},
+		{-5, 1, 2, -3},
+		{4, 1, 3, 1},
+		{5, 1, 3, 2},
+		{1 << 62, 2, 4, 1 << 61},
+	}
+	for _, tt := range tests {
+		if got := mulDivRound(tt.a, tt.num, tt.den); got != tt.want {
+			t.Errorf("mulDivRound(%d, %d, %d) = %d, want %d", tt.a, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package math
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Step is one adjustment made while computing a price
+type Step struct {
+	Label  string  `json:"label"`
+	Change float64 `json:"change"`
+	Result float64 `json:"result"`
+}
+
+// Explanation records how a final price was reached from a base price
+type Explanation struct {
+	BasePrice  float64 `json:"base_price"`
+	Steps      []Step  `json:"steps"`
+	FinalPrice float64 `json:"final_price"`
+}
+
+// CalculateDiscountExplained is CalculateDiscount that also returns every
+// step taken: the base discount, the member discount and the final rounding
+func CalculateDiscountExplained(price float64, discount float64, isMember bool) (float64, Explanation, error) {
+	final, err := CalculateDiscount(price, discount, isMember)
+	if err != nil {
+		return 0, Explanation{}, err
+	}
+	return final, explainDiscount(price, discount, isMember, "", final, roundingLabel(RoundHalfUp, 2)), nil
+}
+
+// CalculateDiscountMoneyExplained is CalculateDiscountMoney with its steps.
+// The explanation is in major units of the price's currency.
+func CalculateDiscountMoneyExplained(price Money, discount float64, isMember bool) (Money, Explanation, error) {
+	final, err := CalculateDiscountMoney(price, discount, isMember)
+	if err != nil {
+		return Money{}, Explanation{}, err
+	}
+	label := roundingLabel(RoundHalfUp, price.Currency.Exponent)
+	return final, explainDiscount(price.Float(), discount, isMember, "", final.Float(), label), nil
+}
+
+// CalculateDiscountRoundedExplained is CalculateDiscountRounded with its steps
+func CalculateDiscountRoundedExplained(price float64, discount float64, isMember bool, mode RoundingMode) (float64, Explanation, error) {
+	final, err := CalculateDiscountRounded(price, discount, isMember, mode)
+	if err != nil {
+		return 0, Explanation{}, err
+	}
+	return final, explainDiscount(price, discount, isMember, "", final, roundingLabel(mode, 2)), nil
+}
+
+// CalculateDiscountWithPolicyExplained is CalculateDiscountWithPolicy with its
+// steps; a price raised by the policy gets a step of its own
+func CalculateDiscountWithPolicyExplained(price float64, discount float64, isMember bool, policy PricePolicy) (float64, Explanation, error) {
+	rounded, e, err := CalculateDiscountExplained(price, discount, isMember)
+	if err != nil {
+		return 0, Explanation{}, err
+	}
+	final, err := policy.Apply(price, rounded)
+	if err != nil {
+		return 0, Explanation{}, err
+	}
+	if final != rounded {
+		e.Steps = append(e.Steps, Step{Label: "Raised by price policy", Change: final - rounded, Result: final})
+	}
+	e.FinalPrice = final
+	return final, e, nil
+}
+
+// CalculateDiscountExplained is the schedule's CalculateDiscount with its
+// steps; the discount step names the promotions that were active
+func (s *Schedule) CalculateDiscountExplained(price float64, discount float64, isMember bool) (float64, Explanation, error) {
+	if discount < 0 || discount > 100 {
+		return 0, Explanation{}, fmt.Errorf("invalid input")
+	}
+	adjusted, active := s.Discount(discount)
+	final, err := CalculateDiscount(price, adjusted, isMember)
+	if err != nil {
+		return 0, Explanation{}, err
+	}
+	label := ""
+	if len(active) > 0 {
+		parts := []string{fmt.Sprintf("%g%% base", discount)}
+		for _, p := range active {
+			parts = append(parts, fmt.Sprintf("%s %g%%", p.Name, p.Discount))
+		}
+		label = fmt.Sprintf("Discount %g%% (%s)", adjusted, strings.Join(parts, " + "))
+	}
+	return final, explainDiscount(price, adjusted, isMember, label, final, roundingLabel(RoundHalfUp, 2)), nil
+}
+
+// explainDiscount lists the steps from price to final shared by every
+// discount calculation. An empty discountLabel gives the plain one.
+func explainDiscount(price, discount float64, isMember bool, discountLabel string, final float64, roundLabel string) Explanation {
+	e := Explanation{BasePrice: price, FinalPrice: final}
+	current := price
+	add := func(label string, next float64) {
+		e.Steps = append(e.Steps, Step{Label: label, Change: next - current, Result: next})
+		current = next
+	}
+	if discount != 0 {
+		if discountLabel == "" {
+			discountLabel = fmt.Sprintf("Discount %g%%", discount)
+		}
+		add(discountLabel, price*(1-discount/100))
+	}
+	if isMember {
+		add("Member discount 5%", current*0.95)
+	}
+	if current != final {
+		add(roundLabel, final)
+	}
+	return e
+}
+
+// roundingLabel describes rounding to places decimals in mode
+func roundingLabel(mode RoundingMode, places int) string {
+	how := map[RoundingMode]string{
+		RoundHalfEven: "Rounding half to even",
+		RoundDown:     "Rounding down",
+		RoundUp:       "Rounding up",
+	}[mode]
+	if how == "" {
+		how = "Rounding"
+	}
+	switch places {
+	case 0:
+		return how + " to whole units"
+	case 1:
+		return how + " to 1 decimal"
+	default:
+		return fmt.Sprintf("%s to %d decimals", how, places)
+	}
+}
+
+// RenderText writes e as a plain-text receipt
+func RenderText(w io.Writer, e Explanation) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-28s %10.2f\n", "Base price", e.BasePrice)
+	for _, s := range e.Steps {
+		fmt.Fprintf(&b, "%-28s %+10.4f\n", s.Label, s.Change)
+	}
+	fmt.Fprintf(&b, "%-28s %10.2f\n", "Final price", e.FinalPrice)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderMarkdown writes e as a Markdown table
+func RenderMarkdown(w io.Writer, e Explanation) error {
+	var b strings.Builder
+	b.WriteString("| Step | Change | Price |\n")
+	b.WriteString("| --- | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| Base price | | %.2f |\n", e.BasePrice)
+	for _, s := range e.Steps {
+		fmt.Fprintf(&b, "| %s | %+.4f | %.4f |\n", s.Label, s.Change, s.Result)
+	}
+	fmt.Fprintf(&b, "| **Final price** | | **%.2f** |\n", e.FinalPrice)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderJSON writes e as an indented JSON document
+func RenderJSON(w io.Writer, e Explanation) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
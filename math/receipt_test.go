@@ -0,0 +1,144 @@
+package math
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestCalculateDiscountExplained(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      float64
+		discount   float64
+		isMember   bool
+		want       float64
+		wantLabels []string
+	}{
+		{"NoAdjustments", 100, 0, false, 100, nil},
+		{"BaseDiscount", 100, 10, false, 90, []string{"Discount 10%"}},
+		{"MemberWithRounding", 19.99, 10, true, 17.09, []string{"Discount 10%", "Member discount 5%", "Rounding to 2 decimals"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e, err := CalculateDiscountExplained(tt.price, tt.discount, tt.isMember)
+			if err != nil {
+				t.Fatalf("CalculateDiscountExplained(%v, %v, %v) failed: %v", tt.price, tt.discount, tt.isMember, err)
+			}
+			want, _ := CalculateDiscount(tt.price, tt.discount, tt.isMember)
+			testutil.EqualFloat(t, got, want, 0)
+			testutil.EqualFloat(t, got, tt.want, 0.001)
+
+			if len(e.Steps) != len(tt.wantLabels) {
+				t.Fatalf("got %d steps %+v, want %v", len(e.Steps), e.Steps, tt.wantLabels)
+			}
+			current := e.BasePrice
+			for i, s := range e.Steps {
+				if s.Label != tt.wantLabels[i] {
+					t.Errorf("step %d label = %q, want %q", i, s.Label, tt.wantLabels[i])
+				}
+				testutil.EqualFloat(t, current+s.Change, s.Result, 1e-9)
+				current = s.Result
+			}
+			testutil.EqualFloat(t, current, e.FinalPrice, 1e-9)
+		})
+	}
+}
+
+func TestExplainedVariants(t *testing.T) {
+	schedule := NewSchedule(FixedClock{Time: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		Promotion{Name: "Summer", Discount: 5})
+
+	tests := []struct {
+		name       string
+		calc       func() (float64, Explanation, error)
+		want       float64
+		wantLabels []string
+	}{
+		{"Money", func() (float64, Explanation, error) {
+			got, e, err := CalculateDiscountMoneyExplained(Money{Amount: 1999, Currency: JPY}, 10, true)
+			return got.Float(), e, err
+		}, 1709, []string{"Discount 10%", "Member discount 5%", "Rounding to whole units"}},
+		{"Rounded", func() (float64, Explanation, error) {
+			return CalculateDiscountRoundedExplained(19.99, 10, true, RoundDown)
+		}, 17.09, []string{"Discount 10%", "Member discount 5%", "Rounding down to 2 decimals"}},
+		{"Policy", func() (float64, Explanation, error) {
+			return CalculateDiscountWithPolicyExplained(100, 50, false, PricePolicy{MinPrice: 60})
+		}, 60, []string{"Discount 50%", "Raised by price policy"}},
+		{"Schedule", func() (float64, Explanation, error) {
+			return schedule.CalculateDiscountExplained(100, 10, false)
+		}, 85, []string{"Discount 15% (10% base + Summer 5%)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e, err := tt.calc()
+			if err != nil {
+				t.Fatalf("failed: %v", err)
+			}
+			testutil.EqualFloat(t, got, tt.want, 0.001)
+			if len(e.Steps) != len(tt.wantLabels) {
+				t.Fatalf("got %d steps %+v, want %v", len(e.Steps), e.Steps, tt.wantLabels)
+			}
+			current := e.BasePrice
+			for i, s := range e.Steps {
+				if s.Label != tt.wantLabels[i] {
+					t.Errorf("step %d label = %q, want %q", i, s.Label, tt.wantLabels[i])
+				}
+				testutil.EqualFloat(t, current+s.Change, s.Result, 1e-9)
+				current = s.Result
+			}
+			testutil.EqualFloat(t, current, e.FinalPrice, 1e-9)
+			testutil.EqualFloat(t, e.FinalPrice, got, 1e-9)
+		})
+	}
+}
+
+func TestCalculateDiscountExplained_InvalidInput(t *testing.T) {
+	_, _, err := CalculateDiscountExplained(-1, 10, false)
+	if err == nil {
+		t.Fatal("CalculateDiscountExplained(-1, 10, false) should return an error")
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	_, e, err := CalculateDiscountExplained(19.99, 10, true)
+	if err != nil {
+		t.Fatalf("CalculateDiscountExplained failed: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := RenderText(&text, e); err != nil {
+		t.Fatalf("RenderText failed: %v", err)
+	}
+	for _, want := range []string{"Base price", "19.99", "Member discount 5%", "Final price", "17.09"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("RenderText output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := RenderMarkdown(&md, e); err != nil {
+		t.Fatalf("RenderMarkdown failed: %v", err)
+	}
+	if !strings.HasPrefix(md.String(), "| Step | Change | Price |") || !strings.Contains(md.String(), "| **Final price** | | **17.09** |") {
+		t.Errorf("RenderMarkdown output is not the expected table:\n%s", md.String())
+	}
+
+	var js bytes.Buffer
+	if err := RenderJSON(&js, e); err != nil {
+		t.Fatalf("RenderJSON failed: %v", err)
+	}
+	var decoded Explanation
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("RenderJSON output is not valid JSON: %v", err)
+	}
+	if decoded.FinalPrice != e.FinalPrice || len(decoded.Steps) != len(e.Steps) {
+		t.Errorf("RenderJSON round trip = %+v, want %+v", decoded, e)
+	}
+}
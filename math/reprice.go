@@ -0,0 +1,198 @@
+package math
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RepriceRule sets the discount for rows whose Column equals Value
+type RepriceRule struct {
+	Column   string
+	Value    string
+	Discount float64
+}
+
+// RepriceOptions configures RepriceCSV. The discount for a row comes from the
+// first matching rule, then DiscountColumn when it is set and non-blank, then
+// Discount.
+type RepriceOptions struct {
+	SKUColumn      string
+	PriceColumn    string
+	DiscountColumn string
+	Discount       float64
+	// MemberColumn, when set, holds a boolean per row; otherwise IsMember applies
+	MemberColumn string
+	IsMember     bool
+	Rules        []RepriceRule
+}
+
+// RowError is a problem with one row of a price list; the row is skipped
+type RowError struct {
+	Line int
+	SKU  string
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d (%s): %v", e.Line, e.SKU, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RepriceReport summarises a RepriceCSV run
+type RepriceReport struct {
+	Rows    int
+	Written int
+	Errors  []*RowError
+}
+
+// RepriceCSV streams a price list from r, discounts every row with
+// CalculateDiscount and writes it to w with original_price, applied_discount
+// and final_price columns appended; an input that already has one of those
+// columns is rejected. Invalid rows are reported rather than aborting the run;
+// the returned error is only for problems with the file as a whole.
+func RepriceCSV(r io.Reader, w io.Writer, opts RepriceOptions) (RepriceReport, error) {
+	if opts.SKUColumn == "" {
+		opts.SKUColumn = "sku"
+	}
+	if opts.PriceColumn == "" {
+		opts.PriceColumn = "price"
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return RepriceReport{}, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{opts.SKUColumn, opts.PriceColumn, opts.DiscountColumn, opts.MemberColumn} {
+		if _, ok := columns[name]; name != "" && !ok {
+			return RepriceReport{}, fmt.Errorf("missing column %q", name)
+		}
+	}
+	for _, rule := range opts.Rules {
+		if _, ok := columns[rule.Column]; !ok {
+			return RepriceReport{}, fmt.Errorf("missing column %q", rule.Column)
+		}
+	}
+
+	added := []string{"original_price", "applied_discount", "final_price"}
+	for _, name := range added {
+		if _, ok := columns[name]; ok {
+			return RepriceReport{}, fmt.Errorf("input already has a %q column", name)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(header, added...)); err != nil {
+		return RepriceReport{}, fmt.Errorf("write header: %w", err)
+	}
+
+	var report RepriceReport
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// A malformed row has no field positions, so its line comes from the
+		// error and FieldPos is only asked about rows that parsed
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Rows++
+			report.Errors = append(report.Errors, &RowError{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("read row %d: %w", report.Rows+1, err)
+		}
+		line, _ := cr.FieldPos(0)
+		report.Rows++
+
+		if len(record) != len(header) {
+			report.Errors = append(report.Errors, &RowError{Line: line, Err: fmt.Errorf("want %d fields, got %d", len(header), len(record))})
+			continue
+		}
+		sku := record[columns[opts.SKUColumn]]
+		price, discount, final, err := repriceRow(record, columns, opts)
+		if err != nil {
+			report.Errors = append(report.Errors, &RowError{Line: line, SKU: sku, Err: err})
+			continue
+		}
+		out := append(record,
+			strconv.FormatFloat(price, 'f', 2, 64),
+			strconv.FormatFloat(discount, 'f', -1, 64),
+			strconv.FormatFloat(final, 'f', 2, 64),
+		)
+		if err := cw.Write(out); err != nil {
+			return report, fmt.Errorf("write line %d: %w", line, err)
+		}
+		report.Written++
+	}
+	cw.Flush()
+	return report, cw.Error()
+}
+
+func repriceRow(record []string, columns map[string]int, opts RepriceOptions) (price, discount, final float64, err error) {
+	price, err = strconv.ParseFloat(strings.TrimSpace(record[columns[opts.PriceColumn]]), 64)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("parse price: %w", err)
+	}
+
+	discount = opts.Discount
+	matched := false
+	for _, rule := range opts.Rules {
+		if strings.TrimSpace(record[columns[rule.Column]]) == rule.Value {
+			discount, matched = rule.Discount, true
+			break
+		}
+	}
+	if !matched && opts.DiscountColumn != "" {
+		if cell := strings.TrimSpace(record[columns[opts.DiscountColumn]]); cell != "" {
+			discount, err = strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("parse discount: %w", err)
+			}
+		}
+	}
+
+	isMember := opts.IsMember
+	if opts.MemberColumn != "" {
+		if cell := strings.TrimSpace(record[columns[opts.MemberColumn]]); cell != "" {
+			isMember, err = strconv.ParseBool(cell)
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("parse member: %w", err)
+			}
+		}
+	}
+
+	final, err = CalculateDiscount(price, discount, isMember)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("price %v, discount %v: %w", price, discount, err)
+	}
+	return price, discount, final, nil
+}
+
+// WriteErrorReport writes row errors as CSV with the columns line,sku,error
+func WriteErrorReport(w io.Writer, errs []*RowError) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"line", "sku", "error"}); err != nil {
+		return err
+	}
+	for _, e := range errs {
+		if err := cw.Write([]string{strconv.Itoa(e.Line), e.SKU, e.Err.Error()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
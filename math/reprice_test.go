@@ -0,0 +1,133 @@
+package math
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRepriceCSV(t *testing.T) {
+	input := "sku,category,price,discount,member\n" +
+		"A1,shoes,100,10,false\n" +
+		"A2,shoes,50,,true\n" +
+		"B1,clearance,80,10,false\n" +
+		"C1,shoes,-5,10,false\n" +
+		"C2,shoes,abc,10,false\n" +
+		"C3,shoes,20,150,false\n"
+
+	opts := RepriceOptions{
+		DiscountColumn: "discount",
+		Discount:       20,
+		MemberColumn:   "member",
+		Rules:          []RepriceRule{{Column: "category", Value: "clearance", Discount: 50}},
+	}
+
+	var out bytes.Buffer
+	report, err := RepriceCSV(strings.NewReader(input), &out, opts)
+	if err != nil {
+		t.Fatalf("RepriceCSV failed: %v", err)
+	}
+
+	want := "sku,category,price,discount,member,original_price,applied_discount,final_price\n" +
+		"A1,shoes,100,10,false,100.00,10,90.00\n" +
+		"A2,shoes,50,,true,50.00,20,38.00\n" +
+		"B1,clearance,80,10,false,80.00,50,40.00\n"
+	if out.String() != want {
+		t.Errorf("RepriceCSV output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if report.Rows != 6 || report.Written != 3 || len(report.Errors) != 3 {
+		t.Fatalf("report = %d rows, %d written, %d errors, want 6, 3, 3", report.Rows, report.Written, len(report.Errors))
+	}
+	wantErrs := []struct {
+		line int
+		sku  string
+		msg  string
+	}{
+		{5, "C1", "invalid input"},
+		{6, "C2", "parse price"},
+		{7, "C3", "invalid input"},
+	}
+	for i, w := range wantErrs {
+		got := report.Errors[i]
+		if got.Line != w.line || got.SKU != w.sku || !strings.Contains(got.Error(), w.msg) {
+			t.Errorf("error %d = %v, want line %d (%s) containing %q", i, got, w.line, w.sku, w.msg)
+		}
+	}
+	var numErr *strconv.NumError
+	if !errors.As(report.Errors[1], &numErr) {
+		t.Errorf("parse price error type = %T, want to wrap *strconv.NumError", report.Errors[1].Err)
+	}
+}
+
+func TestRepriceCSV_FileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		opts       RepriceOptions
+		wantErrMsg string
+	}{
+		{"Empty", "", RepriceOptions{}, "read header"},
+		{"MissingPriceColumn", "sku,cost\nA,1\n", RepriceOptions{}, `missing column "price"`},
+		{"MissingRuleColumn", "sku,price\nA,1\n", RepriceOptions{Rules: []RepriceRule{{Column: "category"}}}, `missing column "category"`},
+		{"OutputColumnCollision", "sku,price,final_price\nA,1,1\n", RepriceOptions{}, `input already has a "final_price" column`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RepriceCSV(strings.NewReader(tt.input), &bytes.Buffer{}, tt.opts)
+			assertError(t, err, true, tt.wantErrMsg)
+		})
+	}
+}
+
+func TestRepriceCSV_RaggedRow(t *testing.T) {
+	input := "sku,price\nA,10\nB\nC,30\n"
+	var out bytes.Buffer
+	report, err := RepriceCSV(strings.NewReader(input), &out, RepriceOptions{Discount: 10})
+	if err != nil {
+		t.Fatalf("RepriceCSV failed: %v", err)
+	}
+	if report.Written != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("report = %+v, want 2 rows written and an error on line 3", report)
+	}
+}
+
+func TestRepriceCSV_MalformedRow(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantWritten int
+		wantLine    int
+	}{
+		{"UnterminatedQuote", "sku,price\nA,10\n\"B,5\n", 1, 3},
+		{"BareQuote", "sku,price\nA,10\nB\"x,5\nC,30\n", 2, 3},
+		{"TextAfterQuote", "sku,price\nA,10\n\"B\"x,5\nC,30\n", 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := RepriceCSV(strings.NewReader(tt.input), &bytes.Buffer{}, RepriceOptions{Discount: 10})
+			if err != nil {
+				t.Fatalf("RepriceCSV failed: %v", err)
+			}
+			if report.Written != tt.wantWritten || len(report.Errors) != 1 || report.Errors[0].Line != tt.wantLine {
+				t.Errorf("report = %+v, want %d rows written and an error on line %d", report, tt.wantWritten, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestWriteErrorReport(t *testing.T) {
+	var out bytes.Buffer
+	errs := []*RowError{{Line: 4, SKU: "X", Err: errors.New("invalid input")}}
+	if err := WriteErrorReport(&out, errs); err != nil {
+		t.Fatalf("WriteErrorReport failed: %v", err)
+	}
+	want := "line,sku,error\n4,X,invalid input\n"
+	if out.String() != want {
+		t.Errorf("WriteErrorReport = %q, want %q", out.String(), want)
+	}
+}
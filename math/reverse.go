@@ -0,0 +1,132 @@
+package math
+
+import (
+	"fmt"
+	"math"
+)
+
+// RoundingMode says how a price is rounded to cents
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero, as CalculateDiscount does
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds halves to the nearest even cent
+	RoundHalfEven
+	// RoundDown truncates toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// Round rounds x to the given number of decimal places
+func (m RoundingMode) Round(x float64, places int) float64 {
+	scale := math.Pow10(places)
+	v := x * scale
+	// Snap values that are a binary representation error away from a whole
+	// number so that 0.9*100 truncates to 90, not 89
+	if r := math.Round(v); math.Abs(v-r) < 1e-9 {
+		v = r
+	}
+	switch m {
+	case RoundHalfEven:
+		v = math.RoundToEven(v)
+	case RoundDown:
+		v = math.Trunc(v)
+	case RoundUp:
+		if t := math.Trunc(v); t != v {
+			v = t + math.Copysign(1, v)
+		}
+	default:
+		v = math.Round(v)
+	}
+	return v / scale
+}
+
+// CalculateDiscountRounded is CalculateDiscount with a choice of rounding mode
+func CalculateDiscountRounded(price float64, discount float64, isMember bool, mode RoundingMode) (float64, error) {
+	if price < 0 || discount < 0 || discount > 100 {
+		return 0, fmt.Errorf("invalid input")
+	}
+	return mode.Round(applyDiscount(price, discount, isMember), 2), nil
+}
+
+// NoExactSolutionError is returned when rounding makes a target price
+// unreachable. Value is the input that gets closest, giving Nearest.
+type NoExactSolutionError struct {
+	Target  float64
+	Nearest float64
+	Value   float64
+}
+
+func (e *NoExactSolutionError) Error() string {
+	return fmt.Sprintf("no exact solution for %.2f: nearest reachable price is %.2f at %.2f", e.Target, e.Nearest, e.Value)
+}
+
+// SolveDiscount returns the discount percentage, to two decimal places, that
+// turns price into target. When several discounts do, it returns the one
+// closest to the unrounded solution.
+func SolveDiscount(price, target float64, isMember bool, mode RoundingMode) (float64, error) {
+	if price <= 0 || target < 0 {
+		return 0, fmt.Errorf("invalid input")
+	}
+	exact := 100 * (1 - target/(price*tierFactor(isMember)))
+	return solve(target, exact, 0.01, 0, 100, func(d float64) (float64, error) {
+		return CalculateDiscountRounded(price, d, isMember, mode)
+	})
+}
+
+// SolvePrice returns the pre-discount price, rounded to the cent, that
+// discount turns into target
+func SolvePrice(target, discount float64, isMember bool, mode RoundingMode) (float64, error) {
+	if target < 0 || discount < 0 || discount >= 100 {
+		return 0, fmt.Errorf("invalid input")
+	}
+	exact := target / ((1 - discount/100) * tierFactor(isMember))
+	return solve(target, exact, 0.01, 0, math.Inf(1), func(p float64) (float64, error) {
+		return CalculateDiscountRounded(p, discount, isMember, mode)
+	})
+}
+
+func tierFactor(isMember bool) float64 {
+	if isMember {
+		return 0.95
+	}
+	return 1
+}
+
+// solve searches the grid of step-sized values around exact, within [lo, hi],
+// for one that f maps to target
+func solve(target, exact, step, lo, hi float64, f func(float64) (float64, error)) (float64, error) {
+	const radius = 5
+	target = roundTo(target, 2)
+	center := math.Round(exact / step)
+
+	best, bestPrice, bestDist := math.NaN(), 0.0, math.Inf(1)
+	found := false
+	for k := -radius; k <= radius; k++ {
+		v := roundTo((center+float64(k))*step, 2)
+		if v < lo || v > hi {
+			continue
+		}
+		got, err := f(v)
+		if err != nil {
+			continue
+		}
+		dist := math.Abs(got - target)
+		match := dist < 1e-9
+		switch {
+		case match && (!found || math.Abs(v-exact) < math.Abs(best-exact)):
+			best, bestPrice, found = v, got, true
+		case !found && dist < bestDist:
+			best, bestPrice, bestDist = v, got, dist
+		}
+	}
+	if found {
+		return best, nil
+	}
+	if math.IsNaN(best) {
+		return 0, fmt.Errorf("invalid input")
+	}
+	return 0, &NoExactSolutionError{Target: target, Nearest: bestPrice, Value: best}
+}
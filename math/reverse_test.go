@@ -0,0 +1,111 @@
+package math
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestRoundingMode_Round(t *testing.T) {
+	tests := []struct {
+		name string
+		mode RoundingMode
+		x    float64
+		want float64
+	}{
+		{"HalfUp", RoundHalfUp, 0.125, 0.13},
+		{"HalfEven", RoundHalfEven, 0.125, 0.12},
+		{"HalfEvenOdd", RoundHalfEven, 0.375, 0.38},
+		{"Down", RoundDown, 0.129, 0.12},
+		{"DownKeepsWholeCents", RoundDown, 0.9 * 100, 90},
+		{"Up", RoundUp, 0.121, 0.13},
+		{"UpNegative", RoundUp, -0.121, -0.13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.EqualFloat(t, tt.mode.Round(tt.x, 2), tt.want, 1e-9)
+		})
+	}
+}
+
+func TestSolveDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    float64
+		target   float64
+		isMember bool
+		mode     RoundingMode
+		want     float64
+		wantErr  bool
+	}{
+		{"Member", 100, 79.99, true, RoundHalfUp, 15.8, false},
+		{"NonMember", 100, 79.99, false, RoundHalfUp, 20.01, false},
+		{"NoDiscountNeeded", 50, 50, false, RoundHalfUp, 0, false},
+		{"FreeItem", 50, 0, true, RoundHalfUp, 100, false},
+		{"RoundDown", 19.99, 17.99, false, RoundDown, 10, false},
+		{"TargetAbovePrice", 50, 60, false, RoundHalfUp, 0, true},
+		{"ZeroPrice", 0, 0, false, RoundHalfUp, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SolveDiscount(tt.price, tt.target, tt.isMember, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SolveDiscount(%v, %v, %v) error = %v, wantErr %v", tt.price, tt.target, tt.isMember, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			testutil.EqualFloat(t, got, tt.want, 1e-9)
+			final, _ := CalculateDiscountRounded(tt.price, got, tt.isMember, tt.mode)
+			testutil.EqualFloat(t, final, tt.target, 1e-9)
+		})
+	}
+}
+
+func TestSolveDiscount_NoExactSolution(t *testing.T) {
+	// Each 0.01% step on 1000 moves the price by 0.10
+	_, err := SolveDiscount(1000, 999.95, false, RoundHalfUp)
+	var noSolution *NoExactSolutionError
+	if !errors.As(err, &noSolution) {
+		t.Fatalf("SolveDiscount(1000, 999.95) error = %v, want *NoExactSolutionError", err)
+	}
+	if noSolution.Nearest != 1000 && noSolution.Nearest != 999.9 {
+		t.Errorf("nearest = %v, want 1000 or 999.90", noSolution.Nearest)
+	}
+}
+
+func TestSolvePrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   float64
+		discount float64
+		isMember bool
+		mode     RoundingMode
+		want     float64
+		wantErr  bool
+	}{
+		{"Member", 79.99, 10, true, RoundHalfUp, 93.56, false},
+		{"HalfUp", 79.98, 10, true, RoundHalfUp, 93.54, false},
+		{"RoundDownNeedsHigherPrice", 79.98, 10, true, RoundDown, 93.55, false},
+		{"NoDiscount", 12.34, 0, false, RoundHalfUp, 12.34, false},
+		{"FullDiscount", 10, 100, false, RoundHalfUp, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SolvePrice(tt.target, tt.discount, tt.isMember, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SolvePrice(%v, %v, %v) error = %v, wantErr %v", tt.target, tt.discount, tt.isMember, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			testutil.EqualFloat(t, got, tt.want, 1e-9)
+			final, _ := CalculateDiscountRounded(got, tt.discount, tt.isMember, tt.mode)
+			testutil.EqualFloat(t, final, tt.target, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package math
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+)
+
+// WeightBand is the shipping cost for parcels up to MaxWeight kilograms
+type WeightBand struct {
+	MaxWeight float64 `json:"max_weight"`
+	Cost      float64 `json:"cost"`
+}
+
+// ShippingZone is a destination zone covering distances up to MaxDistance
+// kilometres. Orders whose discounted subtotal reaches FreeOver ship free;
+// zero disables the threshold.
+type ShippingZone struct {
+	Name        string       `json:"name"`
+	MaxDistance float64      `json:"max_distance"`
+	Bands       []WeightBand `json:"bands"`
+	FreeOver    float64      `json:"free_over"`
+}
+
+// MemberShipping is the shipping benefit for members: Discount percent off
+// and free shipping from a lower FreeOver threshold
+type MemberShipping struct {
+	Discount float64 `json:"discount"`
+	FreeOver float64 `json:"free_over"`
+}
+
+// ShippingTable holds the shipping rates for every zone
+type ShippingTable struct {
+	Zones  []ShippingZone `json:"zones"`
+	Member MemberShipping `json:"member"`
+}
+
+// ShippingQuote is the shipping cost for one parcel and how it was reached
+type ShippingQuote struct {
+	Zone     string
+	Band     WeightBand
+	Base     float64
+	Discount float64
+	Cost     float64
+	// Free says why shipping is free, empty when it is not
+	Free string
+}
+
+// UnknownZoneError is returned when a shipping table has no matching zone
+type UnknownZoneError struct {
+	Zone string
+}
+
+func (e *UnknownZoneError) Error() string {
+	return fmt.Sprintf("unknown shipping zone: %q", e.Zone)
+}
+
+// WeightLimitError is returned when a parcel is heavier than a zone's last band
+type WeightLimitError struct {
+	Weight float64
+	Max    float64
+}
+
+func (e *WeightLimitError) Error() string {
+	return fmt.Sprintf("weight %.3fkg exceeds the %.3fkg limit", e.Weight, e.Max)
+}
+
+// NewShippingTable builds a table from zones, checking that every zone has
+// weight bands. The zones and bands are copied and sorted, zones by
+// MaxDistance and bands by MaxWeight.
+func NewShippingTable(zones []ShippingZone, member MemberShipping) (*ShippingTable, error) {
+	t := &ShippingTable{Zones: make([]ShippingZone, len(zones)), Member: member}
+	for i, z := range zones {
+		if len(z.Bands) == 0 {
+			return nil, fmt.Errorf("zone %q has no weight bands", z.Name)
+		}
+		z.Bands = slices.Clone(z.Bands)
+		sort.SliceStable(z.Bands, func(i, j int) bool { return z.Bands[i].MaxWeight < z.Bands[j].MaxWeight })
+		t.Zones[i] = z
+	}
+	sort.SliceStable(t.Zones, func(i, j int) bool { return t.Zones[i].MaxDistance < t.Zones[j].MaxDistance })
+	return t, nil
+}
+
+// LoadShippingTable reads a shipping table from a JSON file and checks it
+// as NewShippingTable does
+func LoadShippingTable(path string) (*ShippingTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: fmt.Errorf("read shipping table: %w", err)}
+	}
+	var raw ShippingTable
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, &ConfigError{Path: path, Err: fmt.Errorf("parse shipping table: %w", err)}
+	}
+	t, err := NewShippingTable(raw.Zones, raw.Member)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	return t, nil
+}
+
+// Quote returns the cost of shipping weight kilograms to zone for an order
+// whose subtotal, after CalculateDiscount, is subtotal
+func (t *ShippingTable) Quote(zone string, weight, subtotal float64, isMember bool) (ShippingQuote, error) {
+	for _, z := range t.Zones {
+		if z.Name == zone {
+			return t.quote(z, weight, subtotal, isMember)
+		}
+	}
+	return ShippingQuote{}, &UnknownZoneError{Zone: zone}
+}
+
+// QuoteDistance is Quote for the nearest zone covering distance kilometres,
+// the one with the smallest MaxDistance at or above it, whatever the order
+// of Zones
+func (t *ShippingTable) QuoteDistance(distance, weight, subtotal float64, isMember bool) (ShippingQuote, error) {
+	best := -1
+	for i, z := range t.Zones {
+		if distance <= z.MaxDistance && (best < 0 || z.MaxDistance < t.Zones[best].MaxDistance) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ShippingQuote{}, &UnknownZoneError{Zone: fmt.Sprintf("%gkm", distance)}
+	}
+	return t.quote(t.Zones[best], weight, subtotal, isMember)
+}
+
+// quote prices a parcel in zone z. It does not rely on the bands being
+// sorted, so tables built without NewShippingTable quote correctly too.
+func (t *ShippingTable) quote(z ShippingZone, weight, subtotal float64, isMember bool) (ShippingQuote, error) {
+	if weight < 0 || subtotal < 0 {
+		return ShippingQuote{}, fmt.Errorf("invalid input")
+	}
+	if len(z.Bands) == 0 {
+		return ShippingQuote{}, fmt.Errorf("zone %q has no weight bands", z.Name)
+	}
+	i, heaviest := -1, 0
+	for j, b := range z.Bands {
+		if b.MaxWeight >= weight && (i < 0 || b.MaxWeight < z.Bands[i].MaxWeight) {
+			i = j
+		}
+		if b.MaxWeight > z.Bands[heaviest].MaxWeight {
+			heaviest = j
+		}
+	}
+	if i < 0 {
+		return ShippingQuote{}, &WeightLimitError{Weight: weight, Max: z.Bands[heaviest].MaxWeight}
+	}
+
+	q := ShippingQuote{Zone: z.Name, Band: z.Bands[i], Base: z.Bands[i].Cost}
+	switch {
+	case z.FreeOver > 0 && subtotal >= z.FreeOver:
+		q.Free = fmt.Sprintf("subtotal of at least %.2f", z.FreeOver)
+	case isMember && t.Member.FreeOver > 0 && subtotal >= t.Member.FreeOver:
+		q.Free = fmt.Sprintf("member subtotal of at least %.2f", t.Member.FreeOver)
+	}
+	if q.Free != "" {
+		q.Discount = q.Base
+	} else if isMember {
+		q.Discount = roundCents(q.Base * t.Member.Discount / 100)
+	}
+	q.Cost = roundCents(q.Base - q.Discount)
+	return q, nil
+}
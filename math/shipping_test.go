@@ -0,0 +1,162 @@
+package math
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+const testShippingTable = `{
+	"zones": [
+		{"name": "local", "max_distance": 50, "free_over": 75,
+		 "bands": [{"max_weight": 5, "cost": 6.99}, {"max_weight": 1, "cost": 3.99}]},
+		{"name": "national", "max_distance": 1000,
+		 "bands": [{"max_weight": 1, "cost": 7.99}, {"max_weight": 20, "cost": 19.99}]}
+	],
+	"member": {"discount": 50, "free_over": 40}
+}`
+
+func TestShippingTable_Quote(t *testing.T) {
+	table, err := LoadShippingTable(createConfigFile(t, testShippingTable))
+	if err != nil {
+		t.Fatalf("LoadShippingTable failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		zone     string
+		weight   float64
+		subtotal float64
+		isMember bool
+		wantBase float64
+		wantCost float64
+		wantFree bool
+	}{
+		{"LightestBand", "local", 0.5, 20, false, 3.99, 3.99, false},
+		{"BandUpperBoundInclusive", "local", 1, 20, false, 3.99, 3.99, false},
+		{"HeavierBand", "local", 3, 20, false, 6.99, 6.99, false},
+		{"FreeOverThreshold", "local", 3, 75, false, 6.99, 0, true},
+		{"MemberHalfPrice", "national", 10, 20, true, 19.99, 10, false},
+		{"MemberFreeOverLowerThreshold", "national", 10, 40, true, 19.99, 0, true},
+		{"NonMemberNoZoneThreshold", "national", 10, 500, false, 19.99, 19.99, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Quote(tt.zone, tt.weight, tt.subtotal, tt.isMember)
+			if err != nil {
+				t.Fatalf("Quote(%s, %v, %v, %v) failed: %v", tt.zone, tt.weight, tt.subtotal, tt.isMember, err)
+			}
+			testutil.EqualFloat(t, got.Base, tt.wantBase, 0.001)
+			testutil.EqualFloat(t, got.Cost, tt.wantCost, 0.001)
+			testutil.EqualFloat(t, got.Base-got.Discount, got.Cost, 0.001)
+			if (got.Free != "") != tt.wantFree {
+				t.Errorf("Quote(%s, %v, %v, %v).Free = %q, want free %v", tt.zone, tt.weight, tt.subtotal, tt.isMember, got.Free, tt.wantFree)
+			}
+		})
+	}
+}
+
+func TestShippingTable_QuoteDistance(t *testing.T) {
+	table, err := LoadShippingTable(createConfigFile(t, testShippingTable))
+	if err != nil {
+		t.Fatalf("LoadShippingTable failed: %v", err)
+	}
+	subtotal, _ := CalculateDiscount(100, 30, false)
+
+	got, err := table.QuoteDistance(120, 0.8, subtotal, false)
+	if err != nil {
+		t.Fatalf("QuoteDistance failed: %v", err)
+	}
+	if got.Zone != "national" || got.Cost != 7.99 {
+		t.Errorf("QuoteDistance(120km) = %s %.2f, want national 7.99", got.Zone, got.Cost)
+	}
+
+	_, err = table.QuoteDistance(5000, 1, subtotal, false)
+	var zoneErr *UnknownZoneError
+	if !errors.As(err, &zoneErr) {
+		t.Errorf("QuoteDistance(5000km) error = %v, want *UnknownZoneError", err)
+	}
+}
+
+func TestShippingTable_Errors(t *testing.T) {
+	table, err := LoadShippingTable(createConfigFile(t, testShippingTable))
+	if err != nil {
+		t.Fatalf("LoadShippingTable failed: %v", err)
+	}
+
+	var zoneErr *UnknownZoneError
+	if _, err := table.Quote("mars", 1, 10, false); !errors.As(err, &zoneErr) {
+		t.Errorf("Quote(mars) error = %v, want *UnknownZoneError", err)
+	}
+	var weightErr *WeightLimitError
+	if _, err := table.Quote("local", 6, 10, false); !errors.As(err, &weightErr) {
+		t.Errorf("Quote(local, 6kg) error = %v, want *WeightLimitError", err)
+	}
+	if _, err := table.Quote("local", -1, 10, false); err == nil {
+		t.Error("Quote(local, -1kg) should return an error")
+	}
+}
+
+func TestShippingTable_BuiltInCode(t *testing.T) {
+	zones := []ShippingZone{
+		{Name: "national", MaxDistance: 1000, Bands: []WeightBand{{MaxWeight: 20, Cost: 19.99}, {MaxWeight: 1, Cost: 7.99}}},
+		{Name: "local", MaxDistance: 50, Bands: []WeightBand{{MaxWeight: 5, Cost: 3.99}}},
+	}
+
+	// A literal table keeps its order: national is listed before local
+	literal := &ShippingTable{Zones: zones}
+	got, err := literal.QuoteDistance(10, 0.5, 10, false)
+	if err != nil {
+		t.Fatalf("QuoteDistance failed: %v", err)
+	}
+	if got.Zone != "local" || got.Cost != 3.99 {
+		t.Errorf("QuoteDistance(10km) = %s %.2f, want local 3.99", got.Zone, got.Cost)
+	}
+	got, err = literal.Quote("national", 0.5, 10, false)
+	if err != nil {
+		t.Fatalf("Quote failed: %v", err)
+	}
+	if got.Cost != 7.99 {
+		t.Errorf("Quote(national, 0.5kg) with unsorted bands = %.2f, want 7.99", got.Cost)
+	}
+
+	empty := &ShippingTable{Zones: []ShippingZone{{Name: "void", MaxDistance: 10}}}
+	if _, err := empty.Quote("void", 1, 10, false); err == nil {
+		t.Error("Quote in a zone without bands should return an error")
+	}
+	if _, err := NewShippingTable(empty.Zones, MemberShipping{}); err == nil {
+		t.Error("NewShippingTable with a zone without bands should return an error")
+	}
+
+	table, err := NewShippingTable(zones, MemberShipping{})
+	if err != nil {
+		t.Fatalf("NewShippingTable failed: %v", err)
+	}
+	if table.Zones[0].Name != "local" || table.Zones[1].Bands[0].MaxWeight != 1 {
+		t.Errorf("NewShippingTable did not sort zones and bands: %+v", table.Zones)
+	}
+	if zones[0].Bands[0].MaxWeight != 20 {
+		t.Error("NewShippingTable reordered the caller's bands")
+	}
+}
+
+func TestLoadShippingTable_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantErrMsg string
+	}{
+		{"MissingFile", "", "read shipping table: open "},
+		{"InvalidJSON", "{", "parse shipping table"},
+		{"NoBands", `{"zones": [{"name": "local"}]}`, `zone "local" has no weight bands`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadShippingTable(createConfigFile(t, tt.data))
+			assertError(t, err, true, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package math
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TaxMode says whether an amount already includes tax
+type TaxMode int
+
+const (
+	// TaxExclusive treats the amount as net; taxes are added on top
+	TaxExclusive TaxMode = iota
+	// TaxInclusive treats the amount as gross; taxes are extracted from it
+	TaxInclusive
+)
+
+// TaxRounding says where tax amounts are rounded to cents
+type TaxRounding int
+
+const (
+	// RoundPerLine rounds every tax line and sums the rounded lines
+	RoundPerLine TaxRounding = iota
+	// RoundPerTotal sums the unrounded lines and rounds the total once; the
+	// rounded total is then spread over the lines by largest remainder
+	RoundPerTotal
+)
+
+// TaxRate is a single tax expressed as a percentage, e.g. 20 for 20%
+type TaxRate struct {
+	Name string
+	Rate float64
+	// Compound taxes are charged on the net amount plus every tax before them
+	Compound bool
+}
+
+// TaxLine is the amount charged for one TaxRate
+type TaxLine struct {
+	Name   string
+	Rate   float64
+	Amount float64
+}
+
+// TaxBreakdown is the result of applying taxes to an amount
+type TaxBreakdown struct {
+	Net   float64
+	Tax   float64
+	Gross float64
+	Lines []TaxLine
+}
+
+// UnknownRegionError is returned when a tax table has no rates for a region
+type UnknownRegionError struct {
+	Region string
+}
+
+func (e *UnknownRegionError) Error() string {
+	return fmt.Sprintf("unknown tax region: %q", e.Region)
+}
+
+// ApplyTax applies rates in order to amount, typically the result of
+// CalculateDiscount. Net + Tax always equals Gross to the cent.
+func ApplyTax(amount float64, rates []TaxRate, mode TaxMode, rounding TaxRounding) (TaxBreakdown, error) {
+	if amount < 0 {
+		return TaxBreakdown{}, fmt.Errorf("invalid input")
+	}
+	for _, r := range rates {
+		if r.Rate < 0 {
+			return TaxBreakdown{}, fmt.Errorf("invalid tax rate %q: %v", r.Name, r.Rate)
+		}
+	}
+
+	net := amount
+	if mode == TaxInclusive {
+		// Gross is linear in net, so the factor for a net of 1 recovers net
+		_, factor := taxAmounts(1, rates)
+		net = amount / factor
+	}
+	raw, _ := taxAmounts(net, rates)
+
+	b := TaxBreakdown{Lines: make([]TaxLine, len(rates))}
+	var unrounded float64
+	for i, r := range rates {
+		b.Lines[i] = TaxLine{Name: r.Name, Rate: r.Rate, Amount: roundCents(raw[i])}
+		b.Tax += b.Lines[i].Amount
+		unrounded += raw[i]
+	}
+	b.Tax = roundCents(b.Tax)
+	if rounding == RoundPerTotal {
+		b.Tax = roundCents(unrounded)
+		// Spread the rounded total over the lines so that they still add up
+		weights := make([]int64, len(raw))
+		for i, x := range raw {
+			weights[i] = int64(math.Round(x * 1e6))
+		}
+		for i, cents := range allocate(int64(math.Round(b.Tax*100)), weights) {
+			b.Lines[i].Amount = float64(cents) / 100
+		}
+	}
+
+	if mode == TaxInclusive {
+		b.Gross = roundCents(amount)
+		b.Net = roundCents(b.Gross - b.Tax)
+	} else {
+		b.Net = roundCents(net)
+		b.Gross = roundCents(b.Net + b.Tax)
+	}
+	return b, nil
+}
+
+// taxAmounts returns the unrounded tax for each rate and the gross amount
+func taxAmounts(net float64, rates []TaxRate) ([]float64, float64) {
+	amounts := make([]float64, len(rates))
+	gross := net
+	for i, r := range rates {
+		base := net
+		if r.Compound {
+			base = gross
+		}
+		amounts[i] = base * r.Rate / 100
+		gross += amounts[i]
+	}
+	return amounts, gross
+}
+
+func roundCents(x float64) float64 {
+	return roundTo(x, 2)
+}
+
+// TaxTable maps a region code to the rates charged there, in order
+type TaxTable map[string][]TaxRate
+
+// LoadTaxTable reads a tax table from a CSV file with the columns
+// region,name,rate[,compound]. Blank lines and lines starting with # are ignored.
+func LoadTaxTable(path string) (TaxTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: fmt.Errorf("read tax table: %w", err)}
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	table := make(TaxTable)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, &ConfigError{Path: path, Err: fmt.Errorf("read tax table: %w", err)}
+		}
+		line, _ := r.FieldPos(0)
+		if len(record) < 3 || len(record) > 4 {
+			return nil, &ConfigError{Path: path, Err: fmt.Errorf("line %d: want 3 or 4 fields, got %d", line, len(record))}
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, &ConfigError{Path: path, Err: fmt.Errorf("line %d: parse rate: %w", line, err)}
+		}
+		compound := false
+		if len(record) == 4 && strings.TrimSpace(record[3]) != "" {
+			compound, err = strconv.ParseBool(strings.TrimSpace(record[3]))
+			if err != nil {
+				return nil, &ConfigError{Path: path, Err: fmt.Errorf("line %d: parse compound: %w", line, err)}
+			}
+		}
+		region := strings.TrimSpace(record[0])
+		table[region] = append(table[region], TaxRate{
+			Name:     strings.TrimSpace(record[1]),
+			Rate:     rate,
+			Compound: compound,
+		})
+	}
+	return table, nil
+}
+
+// Apply applies the rates of region to amount
+func (t TaxTable) Apply(region string, amount float64, mode TaxMode, rounding TaxRounding) (TaxBreakdown, error) {
+	rates, ok := t[region]
+	if !ok {
+		return TaxBreakdown{}, &UnknownRegionError{Region: region}
+	}
+	return ApplyTax(amount, rates, mode, rounding)
+}
@@ -0,0 +1,122 @@
+package math
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/testing/math/testutil"
+)
+
+func TestApplyTax(t *testing.T) {
+	vat := TaxRate{Name: "VAT", Rate: 20}
+	gst := TaxRate{Name: "GST", Rate: 5}
+	qst := TaxRate{Name: "QST", Rate: 9.975, Compound: true}
+
+	tests := []struct {
+		name      string
+		amount    float64
+		rates     []TaxRate
+		mode      TaxMode
+		rounding  TaxRounding
+		wantNet   float64
+		wantTax   float64
+		wantGross float64
+		wantErr   bool
+	}{
+		{"ExclusiveSingle", 90, []TaxRate{vat}, TaxExclusive, RoundPerLine, 90, 18, 108, false},
+		{"InclusiveSingle", 108, []TaxRate{vat}, TaxInclusive, RoundPerLine, 90, 18, 108, false},
+		{"NoRates", 42.5, nil, TaxExclusive, RoundPerLine, 42.5, 0, 42.5, false},
+		{"ExclusiveCompound", 100, []TaxRate{gst, qst}, TaxExclusive, RoundPerLine, 100, 15.47, 115.47, false},
+		{"InclusiveCompound", 115.47, []TaxRate{gst, qst}, TaxInclusive, RoundPerLine, 100, 15.47, 115.47, false},
+		{"PerLineRounding", 0.15, []TaxRate{{Name: "A", Rate: 10}, {Name: "B", Rate: 10}}, TaxExclusive, RoundPerLine, 0.15, 0.04, 0.19, false},
+		{"PerTotalRounding", 0.15, []TaxRate{{Name: "A", Rate: 10}, {Name: "B", Rate: 10}}, TaxExclusive, RoundPerTotal, 0.15, 0.03, 0.18, false},
+		{"NegativeAmount", -1, []TaxRate{vat}, TaxExclusive, RoundPerLine, 0, 0, 0, true},
+		{"NegativeRate", 10, []TaxRate{{Name: "bad", Rate: -5}}, TaxExclusive, RoundPerLine, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyTax(tt.amount, tt.rates, tt.mode, tt.rounding)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyTax(%v) error = %v, wantErr %v", tt.amount, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			testutil.EqualFloat(t, got.Net, tt.wantNet, 0.001)
+			testutil.EqualFloat(t, got.Tax, tt.wantTax, 0.001)
+			testutil.EqualFloat(t, got.Gross, tt.wantGross, 0.001)
+			testutil.EqualFloat(t, got.Net+got.Tax, got.Gross, 0.001)
+			if len(got.Lines) != len(tt.rates) {
+				t.Errorf("ApplyTax(%v) returned %d lines, want %d", tt.amount, len(got.Lines), len(tt.rates))
+			}
+			var lines float64
+			for _, l := range got.Lines {
+				lines += l.Amount
+			}
+			testutil.EqualFloat(t, lines, got.Tax, 0.001)
+		})
+	}
+}
+
+func TestApplyTax_AfterDiscount(t *testing.T) {
+	price, err := CalculateDiscount(100, 10, true)
+	if err != nil {
+		t.Fatalf("CalculateDiscount failed: %v", err)
+	}
+	got, err := ApplyTax(price, []TaxRate{{Name: "VAT", Rate: 20}}, TaxExclusive, RoundPerLine)
+	if err != nil {
+		t.Fatalf("ApplyTax(%v) failed: %v", price, err)
+	}
+	testutil.EqualFloat(t, got.Gross, 102.6, 0.001)
+}
+
+func TestLoadTaxTable(t *testing.T) {
+	data := "# region,name,rate,compound\n" +
+		"UK,VAT,20\n" +
+		"CA-QC,GST,5\n" +
+		"CA-QC,QST,9.975,true\n"
+	table, err := LoadTaxTable(createConfigFile(t, data))
+	if err != nil {
+		t.Fatalf("LoadTaxTable failed: %v", err)
+	}
+	if len(table["CA-QC"]) != 2 || !table["CA-QC"][1].Compound {
+		t.Fatalf("LoadTaxTable CA-QC = %+v, want GST then compound QST", table["CA-QC"])
+	}
+
+	got, err := table.Apply("UK", 50, TaxExclusive, RoundPerTotal)
+	if err != nil {
+		t.Fatalf("Apply(UK) failed: %v", err)
+	}
+	testutil.EqualFloat(t, got.Gross, 60, 0.001)
+
+	_, err = table.Apply("FR", 50, TaxExclusive, RoundPerTotal)
+	var regionErr *UnknownRegionError
+	if !errors.As(err, &regionErr) {
+		t.Errorf("Apply(FR) error = %v, want *UnknownRegionError", err)
+	}
+}
+
+func TestLoadTaxTable_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantErrMsg string
+	}{
+		{"MissingFile", "", "read tax table: open "},
+		{"BadRate", "UK,VAT,twenty\n", "line 1: parse rate"},
+		{"BadCompound", "UK,VAT,20,maybe\n", "line 1: parse compound"},
+		{"TooFewFields", "UK,VAT\n", "want 3 or 4 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTaxTable(createConfigFile(t, tt.data))
+			assertError(t, err, true, tt.wantErrMsg)
+			var cfgErr *ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Errorf("LoadTaxTable error type = %T, want *ConfigError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package split
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeClass is the subset of Unicode grapheme cluster break properties
+// (UAX #29) that graphemeLen distinguishes
+type graphemeClass int
+
+const (
+	gcOther graphemeClass = iota
+	gcCR
+	gcLF
+	gcControl
+	gcExtend
+	gcZWJ
+	gcSpacingMark
+	gcRegionalIndicator
+	gcL
+	gcV
+	gcT
+	gcLV
+	gcLVT
+	gcPictographic
+)
+
+func classify(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return gcCR
+	case r == '\n':
+		return gcLF
+	case r == 0x200D:
+		return gcZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F,
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return gcExtend
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp):
+		return gcControl
+	case unicode.Is(unicode.Mc, r):
+		return gcSpacingMark
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gcL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gcV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gcT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gcLV
+		}
+		return gcLVT
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r >= 0x2194 && r <= 0x21AA, r >= 0x2300 && r <= 0x23FF, r >= 0x25A0 && r <= 0x27BF,
+		r >= 0x2900 && r <= 0x2BFF, r >= 0x1F000 && r <= 0x1FAFF:
+		return gcPictographic
+	}
+	return gcOther
+}
+
+// graphemeLen returns the length in bytes of the first grapheme cluster of a
+// non-empty s. It follows the extended grapheme cluster rules of UAX #29
+// except Prepend, using approximate ranges for emoji and Hangul.
+func graphemeLen(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && n <= 1 {
+		// Invalid UTF-8 is one cluster per byte
+		return 1
+	}
+	prev := classify(r)
+	pictographic := prev == gcPictographic // ExtPict Extend* seen so far
+	regional := 0
+	if prev == gcRegionalIndicator {
+		regional = 1
+	}
+
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r == utf8.RuneError && size <= 1 {
+			return n
+		}
+		cur := classify(r)
+		if graphemeBreak(prev, cur, pictographic, regional) {
+			return n
+		}
+		switch {
+		case cur == gcRegionalIndicator:
+			regional++
+		case cur == gcPictographic:
+			pictographic = true
+		case cur != gcExtend && cur != gcZWJ:
+			pictographic = false
+		}
+		prev = cur
+		n += size
+	}
+	return n
+}
+
+// graphemeBreak reports whether there is a cluster boundary between a rune of
+// class prev and one of class cur
+func graphemeBreak(prev, cur graphemeClass, pictographic bool, regional int) bool {
+	switch {
+	case prev == gcCR && cur == gcLF: // GB3
+		return false
+	case prev == gcCR, prev == gcLF, prev == gcControl: // GB4
+		return true
+	case cur == gcCR, cur == gcLF, cur == gcControl: // GB5
+		return true
+	case prev == gcL && (cur == gcL || cur == gcV || cur == gcLV || cur == gcLVT): // GB6
+		return false
+	case (prev == gcLV || prev == gcV) && (cur == gcV || cur == gcT): // GB7
+		return false
+	case (prev == gcLVT || prev == gcT) && cur == gcT: // GB8
+		return false
+	case cur == gcExtend, cur == gcZWJ, cur == gcSpacingMark: // GB9, GB9a
+		return false
+	case prev == gcZWJ && cur == gcPictographic && pictographic: // GB11
+		return false
+	case prev == gcRegionalIndicator && cur == gcRegionalIndicator: // GB12, GB13
+		return regional%2 == 0
+	}
+	return true // GB999
+}
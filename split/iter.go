@@ -0,0 +1,84 @@
+package split
+
+import (
+	"bytes"
+	"iter"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// All returns an iterator over the substrings of s separated by sep, with the
+// same fields as Split(s, sep) but without building a slice.
+func All(s, sep string) iter.Seq[string] {
+	return all(s, sep, strings.Index, utf8.DecodeRuneInString)
+}
+
+// AllBytes is All for byte slices. The yielded slices share b's memory.
+func AllBytes(b, sep []byte) iter.Seq[[]byte] {
+	return all(b, sep, bytes.Index, utf8.DecodeRune)
+}
+
+// Fields returns an iterator over the runs of non-space characters in s, as
+// defined by unicode.IsSpace, with the same fields as strings.Fields.
+func Fields(s string) iter.Seq[string] {
+	return fields(s, utf8.DecodeRuneInString)
+}
+
+// FieldsBytes is Fields for byte slices. The yielded slices share b's memory.
+func FieldsBytes(b []byte) iter.Seq[[]byte] {
+	return fields(b, utf8.DecodeRune)
+}
+
+func all[T string | []byte](s, sep T, index func(T, T) int, decode func(T) (rune, int)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if len(sep) == 0 {
+			for len(s) > 0 {
+				_, n := decode(s)
+				if !yield(s[:n]) {
+					return
+				}
+				s = s[n:]
+			}
+			return
+		}
+		found := false
+		for {
+			i := index(s, sep)
+			if i < 0 {
+				break
+			}
+			if !yield(s[:i]) {
+				return
+			}
+			s = s[i+len(sep):]
+			found = true
+		}
+		if len(s) > 0 || !found {
+			yield(s)
+		}
+	}
+}
+
+func fields[T string | []byte](s T, decode func(T) (rune, int)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		start := -1
+		for i := 0; i < len(s); {
+			r, n := decode(s[i:])
+			if unicode.IsSpace(r) {
+				if start >= 0 {
+					if !yield(s[start:i]) {
+						return
+					}
+					start = -1
+				}
+			} else if start < 0 {
+				start = i
+			}
+			i += n
+		}
+		if start >= 0 {
+			yield(s[start:])
+		}
+	}
+}
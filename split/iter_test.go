@@ -0,0 +1,125 @@
+package split
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	inputs := []struct{ s, sep string }{
+		{"a,b,c", ","}, {"a,b,", ","}, {",", ","}, {"a,,", ","}, {"", ","},
+		{"abc", ","}, {"a::b", "::"}, {"aé", ""}, {"", ""}, {"\xff", ""},
+	}
+	for _, in := range inputs {
+		want := Split(in.s, in.sep)
+		if got := slices.Collect(All(in.s, in.sep)); !slices.Equal(want, got) {
+			t.Errorf("All(%q, %q) = %q, Split gives %q", in.s, in.sep, got, want)
+		}
+		var gotBytes []string
+		for b := range AllBytes([]byte(in.s), []byte(in.sep)) {
+			gotBytes = append(gotBytes, string(b))
+		}
+		if !slices.Equal(want, gotBytes) {
+			t.Errorf("AllBytes(%q, %q) = %q, Split gives %q", in.s, in.sep, gotBytes, want)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	for _, s := range []string{"", "   ", " a  b\tc\n", "a", "é x y", "\xff z"} {
+		want := strings.Fields(s)
+		if got := slices.Collect(Fields(s)); !slices.Equal(want, got) {
+			t.Errorf("Fields(%q) = %q, strings.Fields gives %q", s, got, want)
+		}
+		var gotBytes []string
+		for b := range FieldsBytes([]byte(s)) {
+			gotBytes = append(gotBytes, string(b))
+		}
+		if !slices.Equal(want, gotBytes) {
+			t.Errorf("FieldsBytes(%q) = %q, strings.Fields gives %q", s, gotBytes, want)
+		}
+	}
+}
+
+func TestAll_Break(t *testing.T) {
+	var got []string
+	for field := range All("a,b,c", ",") {
+		got = append(got, field)
+		if field == "b" {
+			break
+		}
+	}
+	if want := []string{"a", "b"}; !slices.Equal(want, got) {
+		t.Fatalf("fields = %q, want %q", got, want)
+	}
+	for range Fields("a b c") {
+		break
+	}
+}
+
+func TestAll_Allocs(t *testing.T) {
+	s := strings.Repeat("field,", 100)
+	b := []byte(s)
+	sep := []byte(",")
+	tests := map[string]func(){
+		"All": func() {
+			for range All(s, ",") {
+			}
+		},
+		"AllBytes": func() {
+			for range AllBytes(b, sep) {
+			}
+		},
+		"Fields": func() {
+			for range Fields(s) {
+			}
+		},
+		"FieldsBytes": func() {
+			for range FieldsBytes(b) {
+			}
+		},
+	}
+	for name, fn := range tests {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s allocated %v times, want 0", name, allocs)
+		}
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	s := strings.Repeat("field,", 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Split(s, ",")
+	}
+}
+
+func BenchmarkAll(b *testing.B) {
+	s := strings.Repeat("field,", 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range All(s, ",") {
+		}
+	}
+}
+
+func BenchmarkAllBytes(b *testing.B) {
+	s := bytes.Repeat([]byte("field,"), 1000)
+	sep := []byte(",")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range AllBytes(s, sep) {
+		}
+	}
+}
+
+func BenchmarkFields(b *testing.B) {
+	s := strings.Repeat("field ", 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range Fields(s) {
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package split
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DuplicatePolicy says what a KVParser does with a key that appears twice
+type DuplicatePolicy int
+
+const (
+	// DuplicateError rejects the record with a *DuplicateKeyError
+	DuplicateError DuplicatePolicy = iota
+	// KeepFirst keeps the first value and ignores the rest
+	KeepFirst
+	// KeepLast keeps the last value
+	KeepLast
+	// CollectAll keeps every value in order
+	CollectAll
+)
+
+// DuplicateKeyError reports a key that appears more than once
+type DuplicateKeyError struct {
+	Key string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q", e.Key)
+}
+
+// ValueError reports a value that cannot be converted to its target type
+type ValueError struct {
+	Key, Value string
+	Err        error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("key %q: value %q: %v", e.Key, e.Value, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// Record maps each key to its values. Unless duplicates are collected every
+// key has exactly one value.
+type Record map[string][]string
+
+// Get returns the first value of key, or "" when it is absent
+func (r Record) Get(key string) string {
+	if values := r[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// KVParser parses records such as `a=1; b=2; c="x;y"`. Separators inside
+// quotes or after the escape character do not count, and spaces around keys
+// and values are trimmed before they are unquoted. A pair without KVSep is a
+// key with an empty value; empty pairs are skipped.
+type KVParser struct {
+	// PairSep separates pairs; empty means ";"
+	PairSep string
+	// KVSep separates a key from its value at its first occurrence; empty
+	// means "="
+	KVSep string
+	// Quotes lists the quote characters; empty means `"`
+	Quotes string
+	// Escape, when non-zero, makes the next character literal
+	Escape rune
+	// Duplicates says how repeated keys are handled
+	Duplicates DuplicatePolicy
+}
+
+// ParseKV parses s with the default KVParser: pairs separated by ";", keys
+// and values by "=", double quotes, backslash escapes and no duplicates
+func ParseKV(s string) (Record, error) {
+	return KVParser{Escape: '\\'}.Parse(s)
+}
+
+// Parse splits s into pairs and returns them as a Record. Unterminated quotes
+// are reported as a *QuoteError.
+func (p KVParser) Parse(s string) (Record, error) {
+	pairSep, kvSep := p.PairSep, p.KVSep
+	if pairSep == "" {
+		pairSep = ";"
+	}
+	if kvSep == "" {
+		kvSep = "="
+	}
+	// With no bracket pairs a NestedSplitter finds unquoted separators but
+	// leaves the text as written, so the quotes can be trimmed around
+	raw := NestedSplitter{Sep: pairSep, Pairs: []Pair{}, Quotes: p.quotes(), Escape: p.Escape}
+	unquote := QuotedSplitter{Sep: pairSep, Quotes: p.Quotes, Escape: p.Escape}
+
+	pairs, err := raw.Split(s)
+	if err != nil {
+		return nil, err
+	}
+	record := Record{}
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		raw.Sep = kvSep
+		parts, err := raw.Split(pair)
+		if err != nil {
+			return nil, err
+		}
+		key, value := parts[0], ""
+		if len(parts) > 1 {
+			value = pair[len(key)+len(kvSep):]
+		}
+		if key, err = unquoteField(unquote, key); err != nil {
+			return nil, err
+		}
+		if value, err = unquoteField(unquote, value); err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return nil, fmt.Errorf("empty key in %q", pair)
+		}
+
+		if _, seen := record[key]; !seen {
+			record[key] = []string{value}
+			continue
+		}
+		switch p.Duplicates {
+		case DuplicateError:
+			return nil, &DuplicateKeyError{Key: key}
+		case KeepLast:
+			record[key] = []string{value}
+		case CollectAll:
+			record[key] = append(record[key], value)
+		}
+	}
+	return record, nil
+}
+
+func (p KVParser) quotes() string {
+	if p.Quotes == "" {
+		return `"`
+	}
+	return p.Quotes
+}
+
+// unquoteField trims s and removes its quotes and escapes. Any separator in
+// s is quoted or escaped, so the splitter returns a single field.
+func unquoteField(q QuotedSplitter, s string) (string, error) {
+	fields, err := q.Split(strings.TrimSpace(s))
+	if err != nil {
+		return "", err
+	}
+	return fields[0], nil
+}
+
+// Decode parses s and stores the values in v, which must be a pointer to a
+// map with string keys or to a struct. Struct fields are matched by their
+// `kv` tag, or else by name ignoring case; a tag of "-" skips the field and
+// keys with no field are ignored. Values are converted to strings, bools,
+// integers, floats, time.Duration or any encoding.TextUnmarshaler; a slice
+// field or map element receives every value of a collected key. Conversion
+// failures are reported as a *ValueError.
+func (p KVParser) Decode(s string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+	record, err := p.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	target := rv.Elem()
+	switch {
+	case target.Kind() == reflect.Map && target.Type().Key().Kind() == reflect.String:
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+		for key, values := range record {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := setValues(elem, key, values); err != nil {
+				return err
+			}
+			target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		}
+		return nil
+	case target.Kind() == reflect.Struct:
+		for key, values := range record {
+			field, ok := structField(target, key)
+			if !ok {
+				continue
+			}
+			if err := setValues(field, key, values); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot decode into %T", v)
+}
+
+// structField finds the field for key, preferring an exact tag match
+func structField(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	byName := -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("kv")
+		switch {
+		case tag == "-":
+			continue
+		case tag != "":
+			if tag == key {
+				return v.Field(i), true
+			}
+		case byName < 0 && strings.EqualFold(f.Name, key):
+			byName = i
+		}
+	}
+	if byName < 0 {
+		return reflect.Value{}, false
+	}
+	return v.Field(byName), true
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+func setValues(v reflect.Value, key string, values []string) error {
+	isList := v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 &&
+		!reflect.PointerTo(v.Type()).Implements(textUnmarshalerType)
+	if isList {
+		list := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(list.Index(i), value); err != nil {
+				return &ValueError{Key: key, Value: value, Err: err}
+			}
+		}
+		v.Set(list)
+		return nil
+	}
+	if len(values) > 1 {
+		return &ValueError{Key: key, Value: values[len(values)-1], Err: errors.New("several values for a single-valued target")}
+	}
+	if err := setValue(v, values[0]); err != nil {
+		return &ValueError{Key: key, Value: values[0], Err: err}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(s))
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.Set(reflect.ValueOf(s))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
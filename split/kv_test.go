@@ -0,0 +1,173 @@
+package split
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseKV(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  Record
+	}{
+		"request example": {input: `a=1; b=2; c="x;y"`, want: Record{"a": {"1"}, "b": {"2"}, "c": {"x;y"}}},
+		"quoted spaces":   {input: `k=" v "`, want: Record{"k": {" v "}}},
+		"escaped sep":     {input: `k=a\;b;m=c`, want: Record{"k": {"a;b"}, "m": {"c"}}},
+		"value with sep":  {input: "url=a=b", want: Record{"url": {"a=b"}}},
+		"quoted key":      {input: `"a=b"=c`, want: Record{"a=b": {"c"}}},
+		"no value":        {input: "flag; x=", want: Record{"flag": {""}, "x": {""}}},
+		"empty pairs":     {input: " ;a=1;; ", want: Record{"a": {"1"}}},
+		"empty input":     {input: "", want: Record{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseKV(tc.input)
+			if err != nil {
+				t.Fatalf("ParseKV(%q) failed: %v", tc.input, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("ParseKV(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKVParser_Separators(t *testing.T) {
+	p := KVParser{PairSep: "&", KVSep: ":", Quotes: "'"}
+	got, err := p.Parse("a:1&b:'x&y'&c:\"z\"")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := Record{"a": {"1"}, "b": {"x&y"}, "c": {`"z"`}}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("Parse = %q, want %q", got, want)
+	}
+	if got.Get("a") != "1" || got.Get("missing") != "" {
+		t.Errorf("Get = %q, %q", got.Get("a"), got.Get("missing"))
+	}
+}
+
+func TestKVParser_Duplicates(t *testing.T) {
+	const input = "a=1;b=2;a=3"
+	tests := map[string]struct {
+		policy DuplicatePolicy
+		want   []string
+	}{
+		"first":   {policy: KeepFirst, want: []string{"1"}},
+		"last":    {policy: KeepLast, want: []string{"3"}},
+		"collect": {policy: CollectAll, want: []string{"1", "3"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := KVParser{Duplicates: tc.policy}.Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", input, err)
+			}
+			if !reflect.DeepEqual(tc.want, got["a"]) {
+				t.Fatalf("Parse(%q)[a] = %q, want %q", input, got["a"], tc.want)
+			}
+		})
+	}
+
+	_, err := KVParser{}.Parse(input)
+	var dupErr *DuplicateKeyError
+	if !errors.As(err, &dupErr) || dupErr.Key != "a" {
+		t.Errorf("Parse(%q) error = %v, want *DuplicateKeyError for a", input, err)
+	}
+}
+
+func TestParseKV_Errors(t *testing.T) {
+	var quoteErr *QuoteError
+	if _, err := ParseKV(`a=1;b="x`); !errors.As(err, &quoteErr) || quoteErr.Offset != 6 {
+		t.Errorf("unterminated quote error = %v, want *QuoteError at offset 6", err)
+	}
+	if _, err := ParseKV("=1"); err == nil {
+		t.Error("an empty key should return an error")
+	}
+}
+
+type server struct {
+	Host    string
+	Port    uint16        `kv:"port"`
+	Debug   bool          `kv:"debug"`
+	Timeout time.Duration `kv:"timeout"`
+	Ratio   float64
+	Addr    netip.Addr `kv:"addr"`
+	Tags    []string   `kv:"tag"`
+	Secret  string     `kv:"-"`
+	private string
+}
+
+func TestKVParser_DecodeStruct(t *testing.T) {
+	input := `host=example.com; port=8080; debug=true; timeout=1m30s; RATIO=0.5; addr=10.0.0.1; tag=a; tag="b;c"; Secret=x; private=y; other=z`
+	var got server
+	if err := (KVParser{Duplicates: CollectAll}).Decode(input, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := server{
+		Host:    "example.com",
+		Port:    8080,
+		Debug:   true,
+		Timeout: 90 * time.Second,
+		Ratio:   0.5,
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Tags:    []string{"a", "b;c"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+func TestKVParser_DecodeMap(t *testing.T) {
+	var ints map[string]int
+	if err := (KVParser{}).Decode("a=1; b=-2", &ints); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if want := map[string]int{"a": 1, "b": -2}; !reflect.DeepEqual(want, ints) {
+		t.Fatalf("Decode = %v, want %v", ints, want)
+	}
+
+	lists := map[string][]string{"keep": {"x"}}
+	if err := (KVParser{Duplicates: CollectAll}).Decode("a=1; a=2", &lists); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if want := map[string][]string{"keep": {"x"}, "a": {"1", "2"}}; !reflect.DeepEqual(want, lists) {
+		t.Fatalf("Decode = %v, want %v", lists, want)
+	}
+}
+
+func TestKVParser_DecodeErrors(t *testing.T) {
+	tests := map[string]struct {
+		parser KVParser
+		input  string
+		target any
+	}{
+		"bad int":        {input: "port=http", target: &server{}},
+		"overflow":       {input: "port=70000", target: &server{}},
+		"bad duration":   {input: "timeout=soon", target: &server{}},
+		"several values": {parser: KVParser{Duplicates: CollectAll}, input: "host=a;host=b", target: &server{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.parser.Decode(tc.input, tc.target)
+			var valueErr *ValueError
+			if !errors.As(err, &valueErr) {
+				t.Fatalf("Decode(%q) error = %v, want *ValueError", tc.input, err)
+			}
+		})
+	}
+
+	if err := (KVParser{}).Decode("a=1", server{}); err == nil {
+		t.Error("decoding into a non-pointer should return an error")
+	}
+	var n int
+	if err := (KVParser{}).Decode("a=1", &n); err == nil {
+		t.Error("decoding into an int should return an error")
+	}
+}
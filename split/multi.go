@@ -0,0 +1,141 @@
+package split
+
+import "regexp"
+
+// Matcher finds any of a fixed set of separators in a single pass over the
+// input, in the manner of Aho–Corasick, so the cost of a search does not grow
+// with the number of separators. A Matcher is safe for concurrent use.
+type Matcher struct {
+	nodes []matchNode
+}
+
+type matchNode struct {
+	next  map[byte]int32
+	fail  int32
+	depth int
+	// out is the length of the longest separator that ends at this node,
+	// directly or through a fail link, or 0 when none does
+	out int
+}
+
+// NewMatcher returns a Matcher for seps. Empty separators are ignored.
+func NewMatcher(seps ...string) *Matcher {
+	m := &Matcher{nodes: []matchNode{{}}}
+	for _, sep := range seps {
+		if sep == "" {
+			continue
+		}
+		n := int32(0)
+		for i := 0; i < len(sep); i++ {
+			child, ok := m.nodes[n].next[sep[i]]
+			if !ok {
+				if m.nodes[n].next == nil {
+					m.nodes[n].next = make(map[byte]int32)
+				}
+				child = int32(len(m.nodes))
+				m.nodes[n].next[sep[i]] = child
+				m.nodes = append(m.nodes, matchNode{depth: i + 1})
+			}
+			n = child
+		}
+		m.nodes[n].out = len(sep)
+	}
+
+	// Fail links point at the longest proper suffix that is also a prefix of
+	// some separator; a breadth-first walk sets parents before children
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for c, child := range m.nodes[n].next {
+			m.nodes[child].fail = m.step(m.nodes[n].fail, c)
+			if m.nodes[child].out == 0 {
+				m.nodes[child].out = m.nodes[m.nodes[child].fail].out
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+func (m *Matcher) step(n int32, c byte) int32 {
+	for {
+		if child, ok := m.nodes[n].next[c]; ok {
+			return child
+		}
+		if n == 0 {
+			return 0
+		}
+		n = m.nodes[n].fail
+	}
+}
+
+// Index returns the byte offsets of the leftmost separator in s, preferring
+// the longest when several start at the same offset, or -1, -1 when there is
+// none.
+func (m *Matcher) Index(s string) (start, end int) {
+	start, end = -1, -1
+	n := int32(0)
+	for i := 0; i < len(s); i++ {
+		n = m.step(n, s[i])
+		if out := m.nodes[n].out; out > 0 {
+			if at := i + 1 - out; start < 0 || at < start || at == start && i+1 > end {
+				start, end = at, i+1
+			}
+		}
+		// Every later match starts at or after the text the current state
+		// stands for, so once that is past the best start nothing can beat it
+		if start >= 0 && i+1-m.nodes[n].depth > start {
+			break
+		}
+	}
+	return start, end
+}
+
+// Split slices s around every separator the Matcher finds. Like Split, a
+// trailing separator does not produce an empty final field.
+func (m *Matcher) Split(s string) []string {
+	var result []string
+	for {
+		start, end := m.Index(s)
+		if start < 0 {
+			break
+		}
+		result = append(result, s[:start])
+		s = s[end:]
+	}
+	if s == "" && len(result) > 0 {
+		return result
+	}
+	return append(result, s)
+}
+
+// SplitAny slices s around any of seps. Where separators overlap, the one
+// starting first wins, and the longest of those starting together, so "\r\n"
+// beats "\n". Callers splitting many strings on the same set should build a
+// Matcher once instead.
+func SplitAny(s string, seps ...string) []string {
+	return NewMatcher(seps...).Split(s)
+}
+
+// SplitRegexp slices s around every match of re. Empty matches are not
+// treated as separators, and a trailing match does not produce an empty
+// final field.
+func SplitRegexp(s string, re *regexp.Regexp) []string {
+	var result []string
+	last := 0
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		result = append(result, s[last:loc[0]])
+		last = loc[1]
+	}
+	if last == len(s) && len(result) > 0 {
+		return result
+	}
+	return append(result, s[last:])
+}
@@ -0,0 +1,134 @@
+package split
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSplitAny(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		seps  []string
+		want  []string
+	}{
+		"request example":   {input: "a,b;c\td", seps: []string{",", ";", "\t"}, want: []string{"a", "b", "c", "d"}},
+		"longest wins":      {input: "a\r\nb\nc", seps: []string{"\n", "\r\n"}, want: []string{"a", "b", "c"}},
+		"leftmost wins":     {input: "xabcx", seps: []string{"bc", "abcd", "ab"}, want: []string{"x", "cx"}},
+		"overlapping":       {input: "1aab2", seps: []string{"aab", "a"}, want: []string{"1", "2"}},
+		"suffix separator":  {input: "1she2he3", seps: []string{"she", "he"}, want: []string{"1", "2", "3"}},
+		"trailing dropped":  {input: "a,b;", seps: []string{",", ";"}, want: []string{"a", "b"}},
+		"adjacent":          {input: ",;", seps: []string{",", ";"}, want: []string{"", ""}},
+		"no match":          {input: "abc", seps: []string{",", ";"}, want: []string{"abc"}},
+		"no separators":     {input: "a,b", seps: nil, want: []string{"a,b"}},
+		"empty ignored":     {input: "a,b", seps: []string{"", ","}, want: []string{"a", "b"}},
+		"empty input":       {input: "", seps: []string{","}, want: []string{""}},
+		"multibyte":         {input: "a→b⇒c", seps: []string{"→", "⇒"}, want: []string{"a", "b", "c"}},
+		"partial then miss": {input: "aaab", seps: []string{"aab", "b"}, want: []string{"a"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SplitAny(tc.input, tc.seps...); !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("SplitAny(%q, %q) = %q, want %q", tc.input, tc.seps, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitRegexp(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		expr  string
+		want  []string
+	}{
+		"whitespace runs":  {input: "a  b\t\tc", expr: `\s+`, want: []string{"a", "b", "c"}},
+		"trailing dropped": {input: "a1b22", expr: `\d+`, want: []string{"a", "b"}},
+		"empty matches":    {input: "abc", expr: `x*`, want: []string{"abc"}},
+		"no match":         {input: "abc", expr: `,`, want: []string{"abc"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			re := regexp.MustCompile(tc.expr)
+			if got := SplitRegexp(tc.input, re); !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("SplitRegexp(%q, %q) = %q, want %q", tc.input, tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+// indexAny is the naive search SplitAny replaces: one strings.Index per
+// separator, keeping the leftmost and then longest match
+func indexAny(s string, seps []string) (start, end int) {
+	start, end = -1, -1
+	for _, sep := range seps {
+		if sep == "" {
+			continue
+		}
+		if i := strings.Index(s, sep); i >= 0 && (start < 0 || i < start || i == start && i+len(sep) > end) {
+			start, end = i, i+len(sep)
+		}
+	}
+	return start, end
+}
+
+func FuzzMatcherIndex(f *testing.F) {
+	for _, seed := range [][2]string{{"ushers", "he,she,his,hers"}, {"aaab", "aab,b"}, {"a\r\n", "\n,\r\n"}, {"", ","}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, s, list string) {
+		seps := strings.Split(list, ",")
+		start, end := NewMatcher(seps...).Index(s)
+		wantStart, wantEnd := indexAny(s, seps)
+		if start != wantStart || end != wantEnd {
+			t.Fatalf("Index(%q) with %q = %d, %d, want %d, %d", s, seps, start, end, wantStart, wantEnd)
+		}
+	})
+}
+
+func benchmarkSeparators(n int) []string {
+	seps := make([]string, n)
+	for i := range seps {
+		seps[i] = fmt.Sprintf("<sep%03d>", i)
+	}
+	return seps
+}
+
+func benchmarkInput(seps []string) string {
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteString("field value ")
+		b.WriteString(seps[i%len(seps)])
+	}
+	return b.String()
+}
+
+func BenchmarkSplitAny(b *testing.B) {
+	for _, n := range []int{3, 30, 300} {
+		seps := benchmarkSeparators(n)
+		s := benchmarkInput(seps)
+		b.Run(fmt.Sprintf("matcher/%d", n), func(b *testing.B) {
+			m := NewMatcher(seps...)
+			b.SetBytes(int64(len(s)))
+			for i := 0; i < b.N; i++ {
+				_ = m.Split(s)
+			}
+		})
+		b.Run(fmt.Sprintf("index/%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(s)))
+			for i := 0; i < b.N; i++ {
+				rest := s
+				for {
+					start, end := indexAny(rest, seps)
+					if start < 0 {
+						break
+					}
+					rest = rest[end:]
+				}
+			}
+		})
+	}
+}
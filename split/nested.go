@@ -0,0 +1,131 @@
+package split
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pair is an opening and a closing bracket
+type Pair struct {
+	Open, Close rune
+}
+
+// DefaultPairs are parentheses, square brackets and braces
+var DefaultPairs = []Pair{{'(', ')'}, {'[', ']'}, {'{', '}'}}
+
+// BracketError reports an unbalanced bracket
+type BracketError struct {
+	// Offset is the byte offset of the offending bracket
+	Offset int
+	Char   rune
+	// OpenOffset is the byte offset of the bracket a closing bracket does not
+	// match, or -1 when there is no open bracket
+	OpenOffset int
+	// Unclosed is set when Char is an opening bracket left open at the end
+	Unclosed bool
+}
+
+func (e *BracketError) Error() string {
+	switch {
+	case e.OpenOffset >= 0:
+		return fmt.Sprintf("mismatched %c at offset %d for bracket opened at offset %d", e.Char, e.Offset, e.OpenOffset)
+	case e.Unclosed:
+		return fmt.Sprintf("unclosed %c at offset %d", e.Char, e.Offset)
+	default:
+		return fmt.Sprintf("unexpected %c at offset %d", e.Char, e.Offset)
+	}
+}
+
+// NestedSplitter splits strings on Sep only outside brackets and quotes.
+// Fields are returned as written, brackets and quotes included.
+type NestedSplitter struct {
+	Sep string
+	// Pairs lists the brackets to track; nil means DefaultPairs
+	Pairs []Pair
+	// Quotes lists quote characters whose contents are opaque
+	Quotes string
+	// Escape, when non-zero, makes the next character inert
+	Escape rune
+}
+
+// SplitTopLevel splits s on sep outside the default brackets and outside
+// single or double quotes, with backslash escapes
+func SplitTopLevel(s, sep string) ([]string, error) {
+	return NestedSplitter{Sep: sep, Quotes: `"'`, Escape: '\\'}.Split(s)
+}
+
+// Split splits s on the top-level separators. Unbalanced brackets are
+// reported as a *BracketError and unterminated quotes as a *QuoteError.
+func (n NestedSplitter) Split(s string) ([]string, error) {
+	if n.Sep == "" {
+		return nil, fmt.Errorf("empty separator")
+	}
+	pairs := n.Pairs
+	if pairs == nil {
+		pairs = DefaultPairs
+	}
+
+	type open struct {
+		offset int
+		pair   Pair
+	}
+	var stack []open
+	var fields []string
+	var quote rune
+	start, quoteAt := 0, 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n.Escape != 0 && r == n.Escape && i+size < len(s) {
+			_, next := utf8.DecodeRuneInString(s[i+size:])
+			i += size + next
+			continue
+		}
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			i += size
+			continue
+		}
+		if len(stack) == 0 && strings.HasPrefix(s[i:], n.Sep) {
+			fields = append(fields, s[start:i])
+			i += len(n.Sep)
+			start = i
+			continue
+		}
+
+		switch {
+		case strings.ContainsRune(n.Quotes, r):
+			quote, quoteAt = r, i
+		default:
+			for _, p := range pairs {
+				if r == p.Open {
+					stack = append(stack, open{offset: i, pair: p})
+					break
+				}
+				if r != p.Close {
+					continue
+				}
+				if len(stack) == 0 {
+					return nil, &BracketError{Offset: i, Char: r, OpenOffset: -1}
+				}
+				top := stack[len(stack)-1]
+				if top.pair.Close != r {
+					return nil, &BracketError{Offset: i, Char: r, OpenOffset: top.offset}
+				}
+				stack = stack[:len(stack)-1]
+				break
+			}
+		}
+		i += size
+	}
+	if quote != 0 {
+		return nil, &QuoteError{Offset: quoteAt, Quote: quote}
+	}
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, &BracketError{Offset: top.offset, Char: top.pair.Open, OpenOffset: -1, Unclosed: true}
+	}
+	return append(fields, s[start:]), nil
+}
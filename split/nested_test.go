@@ -0,0 +1,81 @@
+package split
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitTopLevel(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		sep   string
+		want  []string
+	}{
+		"request example": {input: "f(a,b),g[c,d],{e,f}", sep: ",", want: []string{"f(a,b)", "g[c,d]", "{e,f}"}},
+		"nested":          {input: "a(b[c,{d,e}],f),g", sep: ",", want: []string{"a(b[c,{d,e}],f)", "g"}},
+		"quotes":          {input: `"a,(",b`, sep: ",", want: []string{`"a,("`, "b"}},
+		"single quotes":   {input: `'a,]',b`, sep: ",", want: []string{`'a,]'`, "b"}},
+		"escaped bracket": {input: `a\(,b`, sep: ",", want: []string{`a\(`, "b"}},
+		"multi-byte sep":  {input: "(a::b)::c", sep: "::", want: []string{"(a::b)", "c"}},
+		"empty fields":    {input: ",(),", sep: ",", want: []string{"", "()", ""}},
+		"empty input":     {input: "", sep: ",", want: []string{""}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := SplitTopLevel(tc.input, tc.sep)
+			if err != nil {
+				t.Fatalf("SplitTopLevel(%q, %q) failed: %v", tc.input, tc.sep, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("SplitTopLevel(%q, %q) = %q, want %q", tc.input, tc.sep, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNestedSplitter_Pairs(t *testing.T) {
+	splitter := NestedSplitter{Sep: ",", Pairs: []Pair{{'<', '>'}}}
+	got, err := splitter.Split("map<a,b>,(c,d)")
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	want := []string{"map<a,b>", "(c", "d)"}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("Split = %q, want %q", got, want)
+	}
+}
+
+func TestSplitTopLevel_Errors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  BracketError
+	}{
+		"unexpected close": {input: "a),b", want: BracketError{Offset: 1, Char: ')', OpenOffset: -1}},
+		"mismatched close": {input: "x,f(a]", want: BracketError{Offset: 5, Char: ']', OpenOffset: 3}},
+		"unclosed":         {input: "(a,[b]", want: BracketError{Offset: 0, Char: '(', OpenOffset: -1, Unclosed: true}},
+		"after multibyte":  {input: "é{", want: BracketError{Offset: 2, Char: '{', OpenOffset: -1, Unclosed: true}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := SplitTopLevel(tc.input, ",")
+			var bracketErr *BracketError
+			if !errors.As(err, &bracketErr) {
+				t.Fatalf("SplitTopLevel(%q) error = %v, want *BracketError", tc.input, err)
+			}
+			if *bracketErr != tc.want {
+				t.Errorf("SplitTopLevel(%q) error = %+v, want %+v", tc.input, *bracketErr, tc.want)
+			}
+		})
+	}
+
+	var quoteErr *QuoteError
+	if _, err := SplitTopLevel(`a,"(b`, ","); !errors.As(err, &quoteErr) || quoteErr.Offset != 2 {
+		t.Errorf("unterminated quote error = %v, want *QuoteError at offset 2", err)
+	}
+	if _, err := (NestedSplitter{}).Split("a"); err == nil {
+		t.Error("Split with an empty separator should return an error")
+	}
+}
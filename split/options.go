@@ -0,0 +1,172 @@
+package split
+
+import (
+	"slices"
+	"strings"
+)
+
+// Option configures SplitWith
+type Option func(*options)
+
+type options struct {
+	limit        int
+	keepSep      bool
+	omitEmpty    bool
+	keepTrailing bool
+	trim         bool
+	fromRight    bool
+	emptyMode    EmptySepMode
+}
+
+// Limit returns at most n fields; the last field holds the unsplit rest of
+// the string. n <= 0 means no limit.
+func Limit(n int) Option {
+	return func(o *options) { o.limit = n }
+}
+
+// KeepSeparators leaves each separator attached to the end of the field
+// before it, like strings.SplitAfter
+func KeepSeparators() Option {
+	return func(o *options) { o.keepSep = true }
+}
+
+// OmitEmpty drops empty fields. Dropped fields do not count toward Limit.
+func OmitEmpty() Option {
+	return func(o *options) { o.omitEmpty = true }
+}
+
+// KeepTrailingEmpty keeps an empty final field, as after a trailing
+// separator, which SplitWith otherwise drops like Split does
+func KeepTrailingEmpty() Option {
+	return func(o *options) { o.keepTrailing = true }
+}
+
+// TrimSpace removes leading and trailing white space from every field
+func TrimSpace() Option {
+	return func(o *options) { o.trim = true }
+}
+
+// FromRight finds separators from the end of the string, so with Limit the
+// unsplit rest is the first field
+func FromRight() Option {
+	return func(o *options) { o.fromRight = true }
+}
+
+// EmptySep sets how an empty separator divides the string; the default is Runes
+func EmptySep(mode EmptySepMode) Option {
+	return func(o *options) { o.emptyMode = mode }
+}
+
+// SplitWith slices s into the substrings separated by sep, configured by
+// opts. With no options it returns the same fields as Split: an empty final
+// field is dropped unless it is the only one. KeepTrailingEmpty makes it
+// behave like strings.Split.
+func SplitWith(s, sep string, opts ...Option) []string {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	fields := splitWith(s, sep, o)
+	if n := len(fields); !o.keepTrailing && n > 1 && fields[n-1] == "" {
+		fields = fields[:n-1]
+	}
+	return fields
+}
+
+func splitWith(s, sep string, o options) []string {
+	if sep == "" && s == "" {
+		return []string{}
+	}
+
+	matches := separators(s, sep, o)
+	fields := make([]string, 0, len(matches)+1)
+	clean := func(f string) (string, bool) {
+		if o.trim {
+			f = strings.TrimSpace(f)
+		}
+		return f, f != "" || !o.omitEmpty
+	}
+	// add appends the field f unless it is omitted. Once the limit is
+	// reached it reports false, unless f would have been omitted anyway so
+	// that the unsplit rest does not start with empty fields.
+	add := func(f string) bool {
+		f, keep := clean(f)
+		if o.limit > 0 && len(fields) == o.limit-1 {
+			return !keep
+		}
+		if keep {
+			fields = append(fields, f)
+		}
+		return true
+	}
+	addRest := func(f string) {
+		if f, keep := clean(f); keep {
+			fields = append(fields, f)
+		}
+	}
+
+	if o.fromRight {
+		end := len(s)
+		for _, m := range matches {
+			if !add(s[m[1]:end]) {
+				break
+			}
+			end = m[0]
+			if o.keepSep {
+				end = m[1]
+			}
+		}
+		addRest(s[:end])
+		slices.Reverse(fields)
+		return fields
+	}
+
+	start := 0
+	for _, m := range matches {
+		end := m[0]
+		if o.keepSep {
+			end = m[1]
+		}
+		if !add(s[start:end]) {
+			break
+		}
+		start = m[1]
+	}
+	addRest(s[start:])
+	return fields
+}
+
+// separators returns the [start, end) byte offsets of the separators in s,
+// in the order SplitWith consumes them. An empty sep matches, with zero
+// width, every boundary between characters.
+func separators(s, sep string, o options) [][2]int {
+	var matches [][2]int
+	if sep == "" {
+		for i := nextChar(s, o.emptyMode); i < len(s); i += nextChar(s[i:], o.emptyMode) {
+			matches = append(matches, [2]int{i, i})
+		}
+		if o.fromRight {
+			slices.Reverse(matches)
+		}
+		return matches
+	}
+
+	if o.fromRight {
+		for end := len(s); ; {
+			i := strings.LastIndex(s[:end], sep)
+			if i < 0 {
+				return matches
+			}
+			matches = append(matches, [2]int{i, i + len(sep)})
+			end = i
+		}
+	}
+	for start := 0; ; {
+		i := strings.Index(s[start:], sep)
+		if i < 0 {
+			return matches
+		}
+		matches = append(matches, [2]int{start + i, start + i + len(sep)})
+		start += i + len(sep)
+	}
+}
@@ -0,0 +1,85 @@
+package split
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWith(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		sep   string
+		opts  []Option
+		want  []string
+	}{
+		"no options":                  {input: "a/b/c/", sep: "/", want: []string{"a", "b", "c"}},
+		"keep trailing empty":         {input: "a/b/c/", sep: "/", opts: []Option{KeepTrailingEmpty()}, want: []string{"a", "b", "c", ""}},
+		"only separator":              {input: "/", sep: "/", want: []string{""}},
+		"trailing empty after trim":   {input: "a, ", sep: ",", opts: []Option{TrimSpace()}, want: []string{"a"}},
+		"limit keeps rest":            {input: "a/b/", sep: "/", opts: []Option{Limit(2)}, want: []string{"a", "b/"}},
+		"no match":                    {input: "abc", sep: "/", want: []string{"abc"}},
+		"empty input":                 {input: "", sep: "/", want: []string{""}},
+		"limit":                       {input: "a/b/c/d", sep: "/", opts: []Option{Limit(2)}, want: []string{"a", "b/c/d"}},
+		"limit one":                   {input: "a/b", sep: "/", opts: []Option{Limit(1)}, want: []string{"a/b"}},
+		"limit above count":           {input: "a/b", sep: "/", opts: []Option{Limit(5)}, want: []string{"a", "b"}},
+		"keep separators":             {input: "a, b, c", sep: ", ", opts: []Option{KeepSeparators()}, want: []string{"a, ", "b, ", "c"}},
+		"omit empty":                  {input: "/a//b/", sep: "/", opts: []Option{OmitEmpty()}, want: []string{"a", "b"}},
+		"omit empty all":              {input: "///", sep: "/", opts: []Option{OmitEmpty()}, want: []string{}},
+		"trim":                        {input: " a , b ,c ", sep: ",", opts: []Option{TrimSpace()}, want: []string{"a", "b", "c"}},
+		"trim and omit blank":         {input: "a, ,b", sep: ",", opts: []Option{TrimSpace(), OmitEmpty()}, want: []string{"a", "b"}},
+		"omitted fields not counted":  {input: "a,,b,c", sep: ",", opts: []Option{OmitEmpty(), Limit(2)}, want: []string{"a", "b,c"}},
+		"from right":                  {input: "a/b/c", sep: "/", opts: []Option{FromRight()}, want: []string{"a", "b", "c"}},
+		"from right limit":            {input: "a/b/c/d", sep: "/", opts: []Option{FromRight(), Limit(2)}, want: []string{"a/b/c", "d"}},
+		"from right overlapping sep":  {input: "aaa", sep: "aa", opts: []Option{FromRight(), KeepTrailingEmpty()}, want: []string{"a", ""}},
+		"from right trailing dropped": {input: "a/b/", sep: "/", opts: []Option{FromRight()}, want: []string{"a", "b"}},
+		"from left overlapping sep":   {input: "aaa", sep: "aa", want: []string{"", "a"}},
+		"from right keep separators":  {input: "a.b.c", sep: ".", opts: []Option{FromRight(), KeepSeparators(), Limit(2)}, want: []string{"a.b.", "c"}},
+		"empty sep":                   {input: "héy", sep: "", want: []string{"h", "é", "y"}},
+		"empty sep limit":             {input: "abc", sep: "", opts: []Option{Limit(2)}, want: []string{"a", "bc"}},
+		"empty sep from right":        {input: "abc", sep: "", opts: []Option{Limit(2), FromRight()}, want: []string{"ab", "c"}},
+		"empty sep graphemes":         {input: "éx", sep: "", opts: []Option{EmptySep(Graphemes)}, want: []string{"é", "x"}},
+		"empty sep graphemes right":   {input: "🇳🇬🇬🇧", sep: "", opts: []Option{EmptySep(Graphemes), FromRight(), Limit(1)}, want: []string{"🇳🇬🇬🇧"}},
+		"empty sep empty input":       {input: "", sep: "", opts: []Option{Limit(3)}, want: []string{}},
+		"empty sep trim omits spaces": {input: "a b", sep: "", opts: []Option{TrimSpace(), OmitEmpty()}, want: []string{"a", "b"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := SplitWith(tc.input, tc.sep, tc.opts...)
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("SplitWith(%q, %q) = %#v, want %#v", tc.input, tc.sep, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitWith_MatchesStrings(t *testing.T) {
+	inputs := []string{"", "a", "a,b", ",a,,b,", "a,b,c,d,e"}
+	keep := KeepTrailingEmpty()
+	for _, s := range inputs {
+		if got, want := SplitWith(s, ",", keep), strings.Split(s, ","); !reflect.DeepEqual(want, got) {
+			t.Errorf("SplitWith(%q, \",\", KeepTrailingEmpty()) = %q, strings.Split gives %q", s, got, want)
+		}
+		for n := 1; n <= 4; n++ {
+			if got, want := SplitWith(s, ",", Limit(n), keep), strings.SplitN(s, ",", n); !reflect.DeepEqual(want, got) {
+				t.Errorf("SplitWith(%q, \",\", Limit(%d), KeepTrailingEmpty()) = %q, strings.SplitN gives %q", s, n, got, want)
+			}
+		}
+		if got, want := SplitWith(s, ",", KeepSeparators(), keep), strings.SplitAfter(s, ","); !reflect.DeepEqual(want, got) {
+			t.Errorf("SplitWith(%q, \",\", KeepSeparators(), KeepTrailingEmpty()) = %q, strings.SplitAfter gives %q", s, got, want)
+		}
+	}
+}
+
+func TestSplitWith_MatchesSplit(t *testing.T) {
+	inputs := []struct{ s, sep string }{
+		{"", ","}, {"a", ","}, {"a,b", ","}, {",a,,b,", ","}, {",", ","}, {"a,,", ","},
+		{"aaa", "aa"}, {"a::b::", "::"}, {"héy", ""}, {"", ""},
+	}
+	for _, in := range inputs {
+		if got, want := SplitWith(in.s, in.sep), Split(in.s, in.sep); !reflect.DeepEqual(want, got) {
+			t.Errorf("SplitWith(%q, %q) = %q, Split gives %q", in.s, in.sep, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package split
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// QuoteError reports an unterminated quote
+type QuoteError struct {
+	// Offset is the byte offset of the opening quote
+	Offset int
+	Quote  rune
+}
+
+func (e *QuoteError) Error() string {
+	return fmt.Sprintf("unterminated %c quote at offset %d", e.Quote, e.Offset)
+}
+
+// QuotedSplitter splits strings on Sep except inside quotes. The zero value
+// of every field but Sep is usable; a zero QuotedSplitter{Sep: ","} honours
+// double quotes and no escapes.
+type QuotedSplitter struct {
+	Sep string
+	// Quotes lists the quote characters; empty means `"`
+	Quotes string
+	// Escape, when non-zero, makes the next character literal, inside or
+	// outside quotes; an escape at the very end is kept as is
+	Escape rune
+	// DoubledQuotes makes two quote characters inside a quoted section stand
+	// for one literal quote, as in CSV
+	DoubledQuotes bool
+}
+
+// SplitQuoted splits s on sep, honouring double quotes and backslash escapes
+func SplitQuoted(s, sep string) ([]string, error) {
+	return QuotedSplitter{Sep: sep, Escape: '\\'}.Split(s)
+}
+
+// Split splits s and unquotes each field: quote characters are removed and
+// escapes are resolved. A field may mix quoted and unquoted parts.
+func (q QuotedSplitter) Split(s string) ([]string, error) {
+	if q.Sep == "" {
+		return nil, fmt.Errorf("empty separator")
+	}
+	quotes := q.Quotes
+	if quotes == "" {
+		quotes = `"`
+	}
+
+	var fields []string
+	var field strings.Builder
+	var quote rune
+	quoteAt := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case q.Escape != 0 && r == q.Escape && i+size < len(s):
+			next, n := utf8.DecodeRuneInString(s[i+size:])
+			field.WriteRune(next)
+			i += size + n
+			continue
+		case quote != 0 && r == quote:
+			if q.DoubledQuotes && strings.HasPrefix(s[i+size:], string(quote)) {
+				field.WriteRune(quote)
+				i += 2 * size
+				continue
+			}
+			quote = 0
+		case quote != 0:
+			field.WriteString(s[i : i+size])
+		case strings.HasPrefix(s[i:], q.Sep):
+			fields = append(fields, field.String())
+			field.Reset()
+			i += len(q.Sep)
+			continue
+		case strings.ContainsRune(quotes, r):
+			quote, quoteAt = r, i
+		default:
+			field.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	if quote != 0 {
+		return nil, &QuoteError{Offset: quoteAt, Quote: quote}
+	}
+	return append(fields, field.String()), nil
+}
@@ -0,0 +1,108 @@
+package split
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		sep   string
+		want  []string
+	}{
+		"request example":    {input: `a,"b,c",d\,e`, sep: ",", want: []string{"a", "b,c", "d,e"}},
+		"plain":              {input: "a,b", sep: ",", want: []string{"a", "b"}},
+		"empty fields":       {input: `,"",`, sep: ",", want: []string{"", "", ""}},
+		"escaped quote":      {input: `"say \"hi\"",x`, sep: ",", want: []string{`say "hi"`, "x"}},
+		"mixed quoting":      {input: `pre"mid,dle"post`, sep: ",", want: []string{"premid,dlepost"}},
+		"multi-byte sep":     {input: `a::"b::c"::d`, sep: "::", want: []string{"a", "b::c", "d"}},
+		"escaped escape":     {input: `a\\,b`, sep: ",", want: []string{`a\`, "b"}},
+		"trailing escape":    {input: `a\`, sep: ",", want: []string{`a\`}},
+		"single quote plain": {input: `'a,b'`, sep: ",", want: []string{"'a", "b'"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := SplitQuoted(tc.input, tc.sep)
+			if err != nil {
+				t.Fatalf("SplitQuoted(%q, %q) failed: %v", tc.input, tc.sep, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("SplitQuoted(%q, %q) = %q, want %q", tc.input, tc.sep, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuotedSplitter(t *testing.T) {
+	tests := map[string]struct {
+		splitter QuotedSplitter
+		input    string
+		want     []string
+	}{
+		"csv doubled quotes": {
+			splitter: QuotedSplitter{Sep: ",", DoubledQuotes: true},
+			input:    `"a ""quoted"" word",b`,
+			want:     []string{`a "quoted" word`, "b"},
+		},
+		"no escape by default": {
+			splitter: QuotedSplitter{Sep: ","},
+			input:    `a\,b`,
+			want:     []string{`a\`, "b"},
+		},
+		"several quote characters": {
+			splitter: QuotedSplitter{Sep: " ", Quotes: `"'`},
+			input:    `'it"s' "it's" plain`,
+			want:     []string{`it"s`, "it's", "plain"},
+		},
+		"custom escape": {
+			splitter: QuotedSplitter{Sep: ";", Escape: '^'},
+			input:    `a^;b;c`,
+			want:     []string{"a;b", "c"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.splitter.Split(tc.input)
+			if err != nil {
+				t.Fatalf("Split(%q) failed: %v", tc.input, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("Split(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitQuoted_Errors(t *testing.T) {
+	tests := map[string]struct {
+		splitter   QuotedSplitter
+		input      string
+		wantOffset int
+		wantQuote  rune
+	}{
+		"unterminated":          {QuotedSplitter{Sep: ","}, `a,"b,c`, 2, '"'},
+		"escaped closing quote": {QuotedSplitter{Sep: ",", Escape: '\\'}, `"abc\"`, 0, '"'},
+		"after multibyte":       {QuotedSplitter{Sep: ",", Quotes: "'"}, `é,'x`, 3, '\''},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.splitter.Split(tc.input)
+			var quoteErr *QuoteError
+			if !errors.As(err, &quoteErr) {
+				t.Fatalf("Split(%q) error = %v, want *QuoteError", tc.input, err)
+			}
+			if quoteErr.Offset != tc.wantOffset || quoteErr.Quote != tc.wantQuote {
+				t.Errorf("Split(%q) error = %v, want %c at offset %d", tc.input, quoteErr, tc.wantQuote, tc.wantOffset)
+			}
+		})
+	}
+
+	if _, err := (QuotedSplitter{}).Split("a"); err == nil {
+		t.Error("Split with an empty separator should return an error")
+	}
+}
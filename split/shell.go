@@ -0,0 +1,138 @@
+package split
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ShellSplitter splits command lines into words following POSIX shell
+// quoting: single quotes keep everything literal, double quotes allow
+// backslash to escape $ ` " \ and newline, and an unquoted backslash makes the
+// next character literal. A backslash before a newline joins the lines.
+// Expansions such as $VAR are not performed.
+type ShellSplitter struct {
+	// Comments ignores the rest of a line from a # that starts a word
+	Comments bool
+}
+
+// ShellSplit splits s into words without comment handling
+func ShellSplit(s string) ([]string, error) {
+	return ShellSplitter{}.Split(s)
+}
+
+// Split splits s into words. An unterminated quote, or a backslash at the
+// very end, is reported as a *QuoteError.
+func (p ShellSplitter) Split(s string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			i++
+		case c == '#' && !inWord && p.Comments:
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				return words, nil
+			}
+			i += end
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, &QuoteError{Offset: i, Quote: '\\'}
+			}
+			if s[i+1] != '\n' {
+				_, n := utf8.DecodeRuneInString(s[i+1:])
+				word.WriteString(s[i+1 : i+1+n])
+				inWord = true
+				i += 1 + n
+			} else {
+				i += 2
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, &QuoteError{Offset: i, Quote: '\''}
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			inWord = true
+			i += end + 2
+		case c == '"':
+			n, err := readDoubleQuoted(s, i, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i += n
+		default:
+			word.WriteByte(c)
+			inWord = true
+			i++
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// readDoubleQuoted writes the contents of the double-quoted section starting
+// at s[start] to word and returns its length including both quotes
+func readDoubleQuoted(s string, start int, word *strings.Builder) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return i + 1 - start, nil
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+				if s[i+1] != '\n' {
+					word.WriteByte(s[i+1])
+				}
+				i++
+				continue
+			}
+		}
+		word.WriteByte(s[i])
+	}
+	return 0, &QuoteError{Offset: start, Quote: '"'}
+}
+
+// ShellJoin joins words into a command line that ShellSplit, with or without
+// comments, splits back into exactly the same words. Words made only of
+// characters with no special meaning are left bare; others are single-quoted.
+func ShellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = shellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(w string) string {
+	if w == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range w {
+		if !isShellSafe(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return w
+	}
+	// A single quote cannot appear inside single quotes, so close the
+	// quotes, add an escaped quote and reopen them
+	return "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+}
+
+func isShellSafe(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune("_@%+=:,./-", r)
+}
@@ -0,0 +1,111 @@
+package split
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestShellSplit(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		comments bool
+		want     []string
+	}{
+		"empty":                  {input: "", want: []string{}},
+		"blank":                  {input: " \t\n ", want: []string{}},
+		"words":                  {input: "  ls  -la\t/tmp ", want: []string{"ls", "-la", "/tmp"}},
+		"single quotes":          {input: `echo 'a  b' '$HOME \n'`, want: []string{"echo", "a  b", `$HOME \n`}},
+		"double quotes":          {input: `echo "a \"b\" \$c \d"`, want: []string{"echo", `a "b" $c \d`}},
+		"backslash escapes":      {input: `a\ b c\\d \'`, want: []string{"a b", `c\d`, "'"}},
+		"adjacent quoting":       {input: `pre'fix'"ed"post`, want: []string{"prefixedpost"}},
+		"empty quoted word":      {input: `a '' ""`, want: []string{"a", "", ""}},
+		"line continuation":      {input: "a\\\nb c", want: []string{"ab", "c"}},
+		"continuation in quotes": {input: "\"a\\\nb\"", want: []string{"ab"}},
+		"hash without comments":  {input: "a #b", want: []string{"a", "#b"}},
+		"comment":                {input: "a #b c\nd", comments: true, want: []string{"a", "d"}},
+		"comment at end":         {input: "a # rest", comments: true, want: []string{"a"}},
+		"hash inside word":       {input: "a#b", comments: true, want: []string{"a#b"}},
+		"quoted hash":            {input: `'#a' "#b" \#c`, comments: true, want: []string{"#a", "#b", "#c"}},
+		"multibyte escape":       {input: `\é`, want: []string{"é"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ShellSplitter{Comments: tc.comments}.Split(tc.input)
+			if err != nil {
+				t.Fatalf("Split(%q) failed: %v", tc.input, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("Split(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShellSplit_Errors(t *testing.T) {
+	tests := map[string]struct {
+		input      string
+		wantOffset int
+		wantQuote  rune
+	}{
+		"unterminated single": {input: `echo 'abc`, wantOffset: 5, wantQuote: '\''},
+		"unterminated double": {input: `echo "a\"`, wantOffset: 5, wantQuote: '"'},
+		"trailing backslash":  {input: `echo a\`, wantOffset: 6, wantQuote: '\\'},
+		"quote before hash":   {input: `a 'b # c`, wantOffset: 2, wantQuote: '\''},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ShellSplit(tc.input)
+			var quoteErr *QuoteError
+			if !errors.As(err, &quoteErr) {
+				t.Fatalf("ShellSplit(%q) error = %v, want *QuoteError", tc.input, err)
+			}
+			if quoteErr.Offset != tc.wantOffset || quoteErr.Quote != tc.wantQuote {
+				t.Errorf("ShellSplit(%q) error = %v, want %c at offset %d", tc.input, quoteErr, tc.wantQuote, tc.wantOffset)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := map[string]struct {
+		words []string
+		want  string
+	}{
+		"bare":         {words: []string{"ls", "-la", "/tmp/x.txt"}, want: "ls -la /tmp/x.txt"},
+		"space":        {words: []string{"a b"}, want: "'a b'"},
+		"empty":        {words: []string{""}, want: "''"},
+		"single quote": {words: []string{"it's"}, want: `'it'\''s'`},
+		"comment":      {words: []string{"#x"}, want: "'#x'"},
+		"none":         {words: nil, want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ShellJoin(tc.words); got != tc.want {
+				t.Errorf("ShellJoin(%q) = %q, want %q", tc.words, got, tc.want)
+			}
+		})
+	}
+}
+
+func FuzzShellJoin(f *testing.F) {
+	for _, seed := range [][2]string{{"a", "b"}, {"", "it's"}, {"#x", "a b"}, {"\\", "\"\n"}, {"$HOME", "é\xff"}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		words := []string{a, b}
+		joined := ShellJoin(words)
+		for _, comments := range []bool{false, true} {
+			got, err := ShellSplitter{Comments: comments}.Split(joined)
+			if err != nil {
+				t.Fatalf("Split(ShellJoin(%q)) = %q failed: %v", words, joined, err)
+			}
+			if !reflect.DeepEqual(words, got) {
+				t.Fatalf("Split(ShellJoin(%q)) = %q via %q", words, got, joined)
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package split
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Position is a place in the text being split
+type Position struct {
+	// Offset counts bytes and Rune counts code points from the start
+	Offset, Rune int
+	// Line and Column start at 1; a newline ends a line and Column counts
+	// code points
+	Line, Column int
+}
+
+// Span is the extent of a field or a separator. End is just past its last
+// character.
+type Span struct {
+	Start, End Position
+	Separator  bool
+}
+
+// Text returns the part of s that the span covers
+func (sp Span) Text(s string) string {
+	return s[sp.Start.Offset:sp.End.Offset]
+}
+
+// SplitSpans returns the spans of the fields Split(s, sep) returns and of the
+// separators between them, in order. A trailing separator has a span even
+// though no empty field follows it.
+func SplitSpans(s, sep string) []Span {
+	var spans []Span
+	pos := Position{Line: 1, Column: 1}
+	add := func(end int, separator bool) {
+		start := pos
+		pos = advance(s, pos, end)
+		spans = append(spans, Span{Start: start, End: pos, Separator: separator})
+	}
+
+	if sep == "" {
+		for _, field := range Chars(s, Runes) {
+			add(pos.Offset+len(field), false)
+		}
+		return spans
+	}
+	for {
+		i := strings.Index(s[pos.Offset:], sep)
+		if i < 0 {
+			break
+		}
+		add(pos.Offset+i, false)
+		add(pos.Offset+len(sep), true)
+	}
+	if pos.Offset < len(s) || len(spans) == 0 {
+		add(len(s), false)
+	}
+	return spans
+}
+
+// advance moves pos over s up to the byte offset end
+func advance(s string, pos Position, end int) Position {
+	for pos.Offset < end {
+		r, n := utf8.DecodeRuneInString(s[pos.Offset:])
+		pos.Offset += n
+		pos.Rune++
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
@@ -0,0 +1,51 @@
+package split
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSplitSpans(t *testing.T) {
+	s := "é,b\nc,"
+	got := SplitSpans(s, ",")
+	want := []Span{
+		{Start: Position{0, 0, 1, 1}, End: Position{2, 1, 1, 2}},
+		{Start: Position{2, 1, 1, 2}, End: Position{3, 2, 1, 3}, Separator: true},
+		{Start: Position{3, 2, 1, 3}, End: Position{6, 5, 2, 2}},
+		{Start: Position{6, 5, 2, 2}, End: Position{7, 6, 2, 3}, Separator: true},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("SplitSpans(%q) = %+v, want %+v", s, got, want)
+	}
+	if text := got[2].Text(s); text != "b\nc" {
+		t.Errorf("Text = %q, want %q", text, "b\nc")
+	}
+}
+
+func TestSplitSpans_MatchesSplit(t *testing.T) {
+	inputs := []struct{ s, sep string }{
+		{"a,b,c", ","}, {"a,b,", ","}, {",", ","}, {"a,,", ","}, {"", ","},
+		{"abc", ","}, {"a::b", "::"}, {"aé\n", ""}, {"", ""},
+	}
+	for _, in := range inputs {
+		spans := SplitSpans(in.s, in.sep)
+		var fields []string
+		joined := ""
+		for i, sp := range spans {
+			if i > 0 && sp.Start != spans[i-1].End {
+				t.Errorf("SplitSpans(%q, %q): span %d starts at %+v, previous ends at %+v", in.s, in.sep, i, sp.Start, spans[i-1].End)
+			}
+			if !sp.Separator {
+				fields = append(fields, sp.Text(in.s))
+			}
+			joined += sp.Text(in.s)
+		}
+		if want := Split(in.s, in.sep); !slices.Equal(want, fields) {
+			t.Errorf("SplitSpans(%q, %q) fields = %q, Split gives %q", in.s, in.sep, fields, want)
+		}
+		if joined != in.s {
+			t.Errorf("SplitSpans(%q, %q) covers %q", in.s, in.sep, joined)
+		}
+	}
+}
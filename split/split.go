@@ -1,16 +1,61 @@
 package split
 
-import "strings"
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// EmptySepMode says how a string is divided when the separator is empty
+type EmptySepMode int
+
+const (
+	// Runes gives one field per Unicode code point; each byte of invalid
+	// UTF-8 is a field of its own
+	Runes EmptySepMode = iota
+	// Graphemes gives one field per user-perceived character, keeping
+	// combining marks, emoji sequences and flags together
+	Graphemes
+)
 
 // Split slices s into all substrings separated by sep and
 // returns a slice of the substrings between those separators.
+// A trailing separator does not produce an empty final field.
+// An empty sep splits s into its code points, as Chars(s, Runes) does.
 func Split(s, sep string) []string {
-    var result []string
-    i := strings.Index(s, sep)
-    for i > -1 {
-        result = append(result, s[:i])
-        s = s[i+len(sep):]
-        i = strings.Index(s, sep)
-    }
-    return append(result, s)
-}
\ No newline at end of file
+	if sep == "" {
+		return Chars(s, Runes)
+	}
+	var result []string
+	i := strings.Index(s, sep)
+	for i > -1 {
+		result = append(result, s[:i])
+		s = s[i+len(sep):]
+		i = strings.Index(s, sep)
+	}
+	if s == "" && len(result) > 0 {
+		return result
+	}
+	return append(result, s)
+}
+
+// Chars splits s into code points or grapheme clusters according to mode.
+// The fields joined together always give back s.
+func Chars(s string, mode EmptySepMode) []string {
+	result := make([]string, 0, utf8.RuneCountInString(s))
+	for len(s) > 0 {
+		n := nextChar(s, mode)
+		result = append(result, s[:n])
+		s = s[n:]
+	}
+	return result
+}
+
+// nextChar returns the length in bytes of the first field of a non-empty s.
+// It is always at least 1, so callers advancing by it terminate.
+func nextChar(s string, mode EmptySepMode) int {
+	if mode == Graphemes {
+		return graphemeLen(s)
+	}
+	_, n := utf8.DecodeRuneInString(s)
+	return n
+}
@@ -3,8 +3,11 @@
 package split
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSplit(t *testing.T) {
@@ -74,3 +77,97 @@ func TestSplit_MapTableDriven(t *testing.T) {
         }
     }
 }
+
+// mustFinish fails the test if fn does not return within a second
+func mustFinish(t *testing.T, name string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s did not terminate", name)
+	}
+}
+
+func TestSplit_EmptySep(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []string
+	}{
+		"empty":        {input: "", want: []string{}},
+		"ascii":        {input: "abc", want: []string{"a", "b", "c"}},
+		"multibyte":    {input: "héllo", want: []string{"h", "é", "l", "l", "o"}},
+		"combining":    {input: "é", want: []string{"e", "́"}},
+		"invalid utf8": {input: "a\xffb", want: []string{"a", "\xff", "b"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			mustFinish(t, fmt.Sprintf("Split(%q, \"\")", tc.input), func() { got = Split(tc.input, "") })
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("Split(%q, \"\") = %#v, want %#v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChars_Graphemes(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  []string
+	}{
+		"empty":              {input: "", want: []string{}},
+		"ascii":              {input: "ab", want: []string{"a", "b"}},
+		"combining accent":   {input: "éx", want: []string{"é", "x"}},
+		"crlf":               {input: "a\r\nb", want: []string{"a", "\r\n", "b"}},
+		"flags":              {input: "🇳🇬🇬🇧🇺", want: []string{"🇳🇬", "🇬🇧", "🇺"}},
+		"skin tone":          {input: "👋🏽!", want: []string{"👋🏽", "!"}},
+		"zwj family":         {input: "👨‍👩‍👧x", want: []string{"👨‍👩‍👧", "x"}},
+		"variation selector": {input: "❤️", want: []string{"❤️"}},
+		"hangul jamo":        {input: "각가", want: []string{"각", "가"}},
+		"spacing mark":       {input: "कि", want: []string{"कि"}},
+		"invalid utf8":       {input: "\xff́", want: []string{"\xff", "́"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Chars(tc.input, Graphemes)
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("Chars(%q, Graphemes) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func FuzzSplit(f *testing.F) {
+	for _, seed := range []string{"", "a", "a/b/c", "héllo", "é", "\r\n", "🇳🇬🇬", "👨‍👩", "\xff\xfe", "‍‍"} {
+		f.Add(seed, "")
+		f.Add(seed, "/")
+	}
+	f.Fuzz(func(t *testing.T, s, sep string) {
+		var got []string
+		mustFinish(t, fmt.Sprintf("Split(%q, %q)", s, sep), func() { got = Split(s, sep) })
+		// Joining restores s, less a trailing separator Split dropped
+		if joined := strings.Join(got, sep); joined != s && joined+sep != s {
+			t.Fatalf("Split(%q, %q) = %q, joins back to %q", s, sep, got, joined)
+		}
+
+		for _, mode := range []EmptySepMode{Runes, Graphemes} {
+			var chars []string
+			mustFinish(t, fmt.Sprintf("Chars(%q, %d)", s, mode), func() { chars = Chars(s, mode) })
+			if joined := strings.Join(chars, ""); joined != s {
+				t.Fatalf("Chars(%q, %d) = %q, joins back to %q", s, mode, chars, joined)
+			}
+			for _, c := range chars {
+				if c == "" {
+					t.Fatalf("Chars(%q, %d) returned an empty field", s, mode)
+				}
+			}
+		}
+	})
+}
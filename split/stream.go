@@ -0,0 +1,79 @@
+package split
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+	"unicode/utf8"
+)
+
+// ScanSeparator returns a bufio.SplitFunc that yields the fields of the input
+// separated by sep. A separator may straddle reads of any size. As with
+// Split, a trailing separator does not produce an empty final field; unlike
+// Split, an empty input yields no fields at all. An empty sep splits the
+// input into code points; invalid UTF-8 is passed through a byte at a time.
+//
+// The returned function remembers how far it has searched, so that a long
+// field read in many small pieces is scanned only once; each bufio.Scanner
+// needs its own.
+func ScanSeparator(sep string) bufio.SplitFunc {
+	if sep == "" {
+		return scanRunes
+	}
+	delim := []byte(sep)
+	// searched is the length of the data already known to hold no separator
+	// start, except possibly in its last len(sep)-1 bytes
+	searched := 0
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		from := max(searched-len(delim)+1, 0)
+		if i := bytes.Index(data[from:], delim); i >= 0 {
+			searched = 0
+			return from + i + len(delim), data[:from+i], nil
+		}
+		if atEOF {
+			searched = 0
+			if len(data) > 0 {
+				return len(data), data, nil
+			}
+			return 0, nil, nil
+		}
+		searched = len(data)
+		return 0, nil, nil
+	}
+}
+
+// scanRunes is bufio.ScanRunes without the replacement of invalid UTF-8:
+// each token is the bytes utf8.DecodeRune consumed, as in Split
+func scanRunes(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 || !atEOF && !utf8.FullRune(data) {
+		return 0, nil, nil
+	}
+	_, n := utf8.DecodeRune(data)
+	return n, data[:n], nil
+}
+
+// ScanFields returns an iterator over the fields of r separated by sep,
+// following ScanSeparator. A field that does not fit in maxTokenSize bytes
+// together with its separator stops the iteration with bufio.ErrTooLong;
+// maxTokenSize 0 means bufio.MaxScanTokenSize. A read error is yielded once,
+// with an empty field, after whatever data was read before it, and ends the
+// iteration.
+func ScanFields(r io.Reader, sep string, maxTokenSize int) iter.Seq2[string, error] {
+	if maxTokenSize <= 0 {
+		maxTokenSize = bufio.MaxScanTokenSize
+	}
+	return func(yield func(string, error) bool) {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, min(4096, maxTokenSize)), maxTokenSize)
+		sc.Split(ScanSeparator(sep))
+		for sc.Scan() {
+			if !yield(sc.Text(), nil) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
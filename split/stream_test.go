@@ -0,0 +1,132 @@
+package split
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+// collect gathers the fields of ScanFields and the error that ended them
+func collect(r io.Reader, sep string, maxTokenSize int) ([]string, error) {
+	var fields []string
+	for field, err := range ScanFields(r, sep, maxTokenSize) {
+		if err != nil {
+			return fields, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func TestScanFields(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		sep   string
+		want  []string
+	}{
+		"multi-byte sep":   {input: "a<=>b<=>c", sep: "<=>", want: []string{"a", "b", "c"}},
+		"trailing dropped": {input: "a<=>b<=>", sep: "<=>", want: []string{"a", "b"}},
+		"empty fields":     {input: "<=><=>x", sep: "<=>", want: []string{"", "", "x"}},
+		"partial sep":      {input: "a<=b<=>c<", sep: "<=>", want: []string{"a<=b", "c<"}},
+		"overlapping sep":  {input: "xaaby", sep: "aab", want: []string{"x", "y"}},
+		"empty input":      {input: "", sep: ",", want: nil},
+		"empty sep":        {input: "aé", sep: "", want: []string{"a", "é"}},
+		"invalid UTF-8":    {input: "a\xffb\xe2\x82", sep: "", want: []string{"a", "\xff", "b", "\xe2", "\x82"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// One byte at a time makes every separator straddle reads
+			got, err := collect(iotest.OneByteReader(strings.NewReader(tc.input)), tc.sep, 0)
+			if err != nil {
+				t.Fatalf("ScanFields(%q, %q) failed: %v", tc.input, tc.sep, err)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("ScanFields(%q, %q) = %q, want %q", tc.input, tc.sep, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScanFields_MaxTokenSize(t *testing.T) {
+	fields, err := collect(strings.NewReader("short||"+strings.Repeat("x", 100)+"||z"), "||", 16)
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("error = %v, want bufio.ErrTooLong", err)
+	}
+	if want := []string{"short"}; !reflect.DeepEqual(want, fields) {
+		t.Fatalf("fields before the error = %q, want %q", fields, want)
+	}
+
+	fields, err = collect(strings.NewReader(strings.Repeat("x", 14)+"||y"), "||", 16)
+	if err != nil || len(fields) != 2 {
+		t.Fatalf("a field that fits with its separator = %q, %v", fields, err)
+	}
+}
+
+func TestScanFields_ReadError(t *testing.T) {
+	boom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("a,b,c"), iotest.ErrReader(boom))
+	fields, err := collect(r, ",", 0)
+	if !errors.Is(err, boom) {
+		t.Fatalf("error = %v, want %v", err, boom)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(want, fields) {
+		t.Fatalf("fields before the error = %q, want %q", fields, want)
+	}
+}
+
+func TestScanFields_Break(t *testing.T) {
+	var got []string
+	for field := range ScanFields(strings.NewReader("a,b,c"), ",", 0) {
+		got = append(got, field)
+		if field == "b" {
+			break
+		}
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("fields = %q, want %q", got, want)
+	}
+}
+
+// chunkReader returns the input in pieces of the given sizes, cycling
+type chunkReader struct {
+	s     string
+	sizes []byte
+	n     int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.s == "" {
+		return 0, io.EOF
+	}
+	size := max(int(r.sizes[r.n%len(r.sizes)]), 1)
+	r.n++
+	n := copy(p[:min(size, len(p))], r.s)
+	r.s = r.s[n:]
+	return n, nil
+}
+
+func FuzzScanFields(f *testing.F) {
+	for _, seed := range [][3]string{{"a<=>b", "<=>", "\x01"}, {"xaaby", "aab", "\x02\x01"}, {"a,,", ",", "\x07"}, {"a\xffé\xe2\x82", "", "\x01"}, {"\xdb", "", "\x01"}} {
+		f.Add(seed[0], seed[1], []byte(seed[2]))
+	}
+	f.Fuzz(func(t *testing.T, s, sep string, sizes []byte) {
+		if s == "" || len(sizes) == 0 {
+			t.Skip()
+		}
+		// An empty sep may look up to a whole rune ahead to tell a truncated
+		// rune from an invalid byte
+		got, err := collect(&chunkReader{s: s, sizes: sizes}, sep, len(s)+max(len(sep), utf8.UTFMax))
+		if err != nil {
+			t.Fatalf("ScanFields(%q, %q) failed: %v", s, sep, err)
+		}
+		if want := Split(s, sep); !reflect.DeepEqual(want, got) {
+			t.Fatalf("ScanFields(%q, %q) = %q, Split gives %q", s, sep, got, want)
+		}
+	})
+}